@@ -0,0 +1,127 @@
+package webfinger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	ActivityStreamsProfile = "https://www.w3.org/ns/activitystreams"
+	activityStreamsLDType  = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+	activityPubAccept      = "application/activity+json, " + activityStreamsLDType
+)
+
+var ErrActorNotFound = errors.New("activitypub actor not found")
+
+type Actor struct {
+	ID                string          `json:"id"`
+	Type              string          `json:"type"`
+	Inbox             string          `json:"inbox"`
+	Outbox            string          `json:"outbox"`
+	PreferredUsername string          `json:"preferredUsername"`
+	PublicKey         *ActorPublicKey `json:"publicKey,omitempty"`
+}
+
+type ActorPublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+func IsActivityPubMediaType(mediaType string) bool {
+	return MediaTypeMatches(mediaType, "application/activity+json", MatchBaseType) ||
+		MediaTypeMatches(mediaType, activityStreamsLDType, MatchParameterSubset)
+}
+
+func (client *Client) ResolveActorURL(ctx context.Context, handle string) (string, error) {
+	webFingerMessage, err := client.Lookup(ctx, handle, "self")
+	if err != nil {
+		return "", err
+	}
+
+	for _, link := range webFingerMessage.Links {
+		if link.Href != "" && RelationTypesEqual(link.Rel, "self") && IsActivityPubMediaType(link.Type) {
+			return link.Href, nil
+		}
+	}
+
+	return "", &Error{
+		Err: ErrActorNotFound,
+	}
+}
+
+func (client *Client) FetchActor(ctx context.Context, handle string) (*Actor, error) {
+	actorURL, err := client.ResolveActorURL(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.FetchActorURL(ctx, actorURL)
+}
+
+func (client *Client) FetchActorURL(ctx context.Context, actorURL string) (*Actor, error) {
+	u, err := url.Parse(actorURL)
+	if err != nil {
+		return nil, &Error{
+			Err: err,
+		}
+	}
+
+	if u.Scheme != "https" && (u.Scheme != "http" || !client.HTTPMode) {
+		return nil, &Error{
+			Err: ErrInvalidResponse,
+		}
+	}
+
+	request, err := client.newHTTPRequest(ctx, actorURL, activityPubAccept)
+	if err != nil {
+		return nil, &Error{
+			Err: err,
+		}
+	}
+
+	response, err := client.send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if err := client.statusCodeToError(response); err != nil {
+		return nil, err
+	}
+
+	contentType := response.Header.Get("Content-Type")
+	if !IsActivityPubMediaType(contentType) {
+		return nil, &Error{
+			Err: newDecodeError(response, contentType, &UnsupportedContentTypeError{
+				ContentType: contentType,
+			}),
+		}
+	}
+
+	b, err := client.readBody(response)
+	if err != nil {
+		return nil, &Error{
+			Err: newTransportError(ctx, response.Request, err),
+		}
+	}
+
+	var actor Actor
+	if err := json.Unmarshal(b, &actor); err != nil {
+		return nil, &Error{
+			Err: newDecodeError(response, contentType, err),
+		}
+	}
+
+	id, err := url.Parse(actor.ID)
+	if err != nil || !id.IsAbs() || !strings.EqualFold(id.Host, response.Request.URL.Host) {
+		return nil, &Error{
+			Err: ErrInvalidResponse,
+		}
+	}
+
+	return &actor, nil
+}
@@ -0,0 +1,106 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_IsActivityPubMediaType(t *testing.T) {
+	tests := []struct {
+		MediaType string
+		Expected  bool
+	}{
+		{MediaType: "application/activity+json", Expected: true},
+		{MediaType: "application/activity+json; charset=utf-8", Expected: true},
+		{MediaType: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, Expected: true},
+		{MediaType: `application/ld+json;profile="https://www.w3.org/ns/activitystreams"`, Expected: true},
+		{MediaType: `Application/LD+JSON; charset=utf-8; profile="http://example.com/profile https://www.w3.org/ns/activitystreams"`, Expected: true},
+		{MediaType: "application/ld+json", Expected: false},
+		{MediaType: `application/ld+json; profile="https://example.com/profile"`, Expected: false},
+		{MediaType: "text/html", Expected: false},
+		{MediaType: "", Expected: false},
+	}
+
+	for i, test := range tests {
+		actual := webfinger.IsActivityPubMediaType(test.MediaType)
+		if test.Expected != actual {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, test.Expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_FetchActor(t *testing.T) {
+	var baseURL string
+	var host string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			if r.URL.Query().Get("rel") != "self" {
+				t.Errorf("unexpected query value: %s: %s", "rel", r.URL.Query().Get("rel"))
+			}
+
+			w.Header().Set("Content-Type", "application/jrd+json")
+			switch r.URL.Query().Get("resource") {
+			case "acct:test@" + host:
+				io.WriteString(w, `{"subject":"acct:test@`+host+`","links":[{"rel":"self","type":"text/html","href":"`+baseURL+`/@test"},{"rel":"self","type":"application/ld+json; profile=\"https://www.w3.org/ns/activitystreams\"","href":"`+baseURL+`/users/test"}]}`)
+			case "acct:spoof@" + host:
+				io.WriteString(w, `{"subject":"acct:spoof@`+host+`","links":[{"rel":"self","type":"application/activity+json","href":"`+baseURL+`/users/spoof"}]}`)
+			default:
+				io.WriteString(w, `{"subject":"acct:noactor@`+host+`","links":[{"rel":"self","type":"text/html","href":"`+baseURL+`/@noactor"}]}`)
+			}
+		case r.URL.Path == "/users/test":
+			w.Header().Set("Content-Type", "application/activity+json")
+			io.WriteString(w, `{"@context":["https://www.w3.org/ns/activitystreams"],"id":"`+baseURL+`/users/test","type":"Person","inbox":"`+baseURL+`/users/test/inbox","outbox":"`+baseURL+`/users/test/outbox","preferredUsername":"test","publicKey":{"id":"`+baseURL+`/users/test#main-key","owner":"`+baseURL+`/users/test","publicKeyPem":"-----BEGIN PUBLIC KEY-----"}}`)
+		case r.URL.Path == "/users/spoof":
+			w.Header().Set("Content-Type", "application/activity+json")
+			io.WriteString(w, `{"id":"https://example.com/users/admin","type":"Person"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	baseURL = testServer.URL
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	actor, err := client.FetchActor(context.Background(), "@test@"+host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actor.ID != baseURL+"/users/test" || actor.Inbox != baseURL+"/users/test/inbox" || actor.PreferredUsername != "test" {
+		t.Errorf("unexpected actor: %v", actor)
+	}
+
+	if actor.PublicKey == nil || actor.PublicKey.Owner != actor.ID {
+		t.Errorf("unexpected public key: %v", actor.PublicKey)
+	}
+
+	if _, err := client.ResolveActorURL(context.Background(), "acct:noactor@"+host); !errors.Is(err, webfinger.ErrActorNotFound) {
+		t.Error(err)
+	}
+
+	if _, err := client.FetchActor(context.Background(), "acct:spoof@"+host); !errors.Is(err, webfinger.ErrInvalidResponse) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,115 @@
+package webfinger
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+const DefaultBatchWorkers = 8
+
+type BatchOptions struct {
+	Workers int
+}
+
+type BatchResult struct {
+	Index   int
+	Request Request
+	Message *Message
+	Err     error
+}
+
+func (client *Client) LookupMany(ctx context.Context, requests []Request, opts *BatchOptions) []BatchResult {
+	results := make([]BatchResult, len(requests))
+	client.runBatch(ctx, requests, opts, func(result BatchResult) {
+		results[result.Index] = result
+	})
+
+	return results
+}
+
+func (client *Client) LookupManyStream(ctx context.Context, requests []Request, opts *BatchOptions) <-chan BatchResult {
+	results := make(chan BatchResult, batchWorkers(opts))
+	go func() {
+		defer close(results)
+		client.runBatch(ctx, requests, opts, func(result BatchResult) {
+			select {
+			case results <- result:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return results
+}
+
+func (client *Client) runBatch(ctx context.Context, requests []Request, opts *BatchOptions, emit func(BatchResult)) {
+	groups := make([][]int, 0, len(requests))
+	groupIndexes := map[string]int{}
+	for i := range requests {
+		key := batchKey(client.HTTPMode, &requests[i])
+		if j, ok := groupIndexes[key]; ok {
+			groups[j] = append(groups[j], i)
+			continue
+		}
+		groupIndexes[key] = len(groups)
+		groups = append(groups, []int{i})
+	}
+
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	for range min(batchWorkers(opts), len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				var webFingerMessage *Message
+				var err error
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = &Error{
+						Err: ctxErr,
+					}
+				} else {
+					webFingerMessage, err = client.DoContext(ctx, &requests[group[0]])
+				}
+
+				for n, i := range group {
+					result := BatchResult{
+						Index:   i,
+						Request: requests[i],
+						Err:     err,
+					}
+					if webFingerMessage != nil {
+						if n == len(group)-1 {
+							result.Message = webFingerMessage
+						} else {
+							result.Message = webFingerMessage.clone()
+						}
+					}
+					emit(result)
+				}
+			}
+		}()
+	}
+
+	for _, group := range groups {
+		jobs <- group
+	}
+	close(jobs)
+
+	wg.Wait()
+}
+
+func batchWorkers(opts *BatchOptions) int {
+	if opts == nil || opts.Workers <= 0 {
+		return DefaultBatchWorkers
+	}
+
+	return opts.Workers
+}
+
+// batchKey also separates validation modes, because validation runs once per
+// group and its outcome depends on the mode of the request.
+func batchKey(httpMode bool, webFingerRequest *Request) string {
+	return cacheKey(httpMode, webFingerRequest) + "\n" + strconv.Itoa(int(webFingerRequest.ValidationMode))
+}
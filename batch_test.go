@@ -0,0 +1,185 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func newBatchTestServer(t *testing.T) (*httptest.Server, string, *atomic.Int32, *atomic.Int32) {
+	var active atomic.Int32
+	var maxActive atomic.Int32
+
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		resource := r.URL.Query().Get("resource")
+		if resource == "acct:notfound@localhost" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"`+resource+`"}`)
+	})
+
+	return testServer, host, count, &maxActive
+}
+
+func Test_Client_LookupMany(t *testing.T) {
+	testServer, host, count, maxActive := newBatchTestServer(t)
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	requests := []webfinger.Request{
+		{Host: host, Resource: "acct:a@localhost"},
+		{Host: host, Resource: "acct:b@localhost"},
+		{Host: host, Resource: "acct:notfound@localhost"},
+		{Host: host, Resource: "acct:a@localhost"},
+		{Host: host, Resource: "acct:c@localhost"},
+		{Host: host, Resource: "acct:d@localhost"},
+	}
+
+	results := client.LookupMany(context.Background(), requests, &webfinger.BatchOptions{Workers: 2})
+	if len(results) != len(requests) {
+		t.Fatalf("unexpected length: %d", len(results))
+	}
+
+	for i, result := range results {
+		if result.Index != i || result.Request.Resource != requests[i].Resource {
+			t.Logf("case_index: %d, unexpected result: %v", i, result)
+			t.Fail()
+			continue
+		}
+
+		if requests[i].Resource == "acct:notfound@localhost" {
+			if !errors.Is(result.Err, webfinger.ErrResourceNotFound) {
+				t.Logf("case_index: %d, unexpected error: %v", i, result.Err)
+				t.Fail()
+			}
+			continue
+		}
+
+		if result.Err != nil || result.Message.Subject != requests[i].Resource {
+			t.Logf("case_index: %d, unexpected result: %v", i, result)
+			t.Fail()
+		}
+	}
+
+	if results[0].Message == results[3].Message {
+		t.Error("duplicated requests share the same message")
+	}
+
+	if count.Load() != 5 {
+		t.Errorf("unexpected request count: %d", count.Load())
+	}
+
+	if maxActive.Load() > 2 {
+		t.Errorf("unexpected concurrency: %d", maxActive.Load())
+	}
+}
+
+func Test_Client_LookupManyStream(t *testing.T) {
+	testServer, host, _, _ := newBatchTestServer(t)
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		Limiter:    webfinger.NewHostLimiter(1000, 100, 1),
+	}
+
+	requests := make([]webfinger.Request, 0, 20)
+	for _, user := range "abcdefghijklmnopqrst" {
+		requests = append(requests, webfinger.Request{Host: host, Resource: "acct:" + string(user) + "@localhost"})
+	}
+
+	seen := map[int]bool{}
+	for result := range client.LookupManyStream(context.Background(), requests, nil) {
+		if result.Err != nil {
+			t.Error(result.Err)
+			continue
+		}
+
+		if result.Message.Subject != requests[result.Index].Resource {
+			t.Errorf("unexpected result: %v", result)
+		}
+
+		seen[result.Index] = true
+	}
+
+	if len(seen) != len(requests) {
+		t.Errorf("unexpected result count: %d", len(seen))
+	}
+}
+
+func Test_Client_LookupMany_Canceled(t *testing.T) {
+	testServer, host, _, _ := newBatchTestServer(t)
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := client.LookupMany(ctx, []webfinger.Request{{Host: host, Resource: "acct:a@localhost"}}, nil)
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Error(results[0].Err)
+	}
+}
+
+func Test_Client_LookupMany_ValidationMode(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:a@localhost","links":[{"href":"https://localhost/@a"}]}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	results := client.LookupMany(context.Background(), []webfinger.Request{
+		{Host: u.Host, Resource: "acct:a@localhost", ValidationMode: webfinger.ValidationLenient},
+		{Host: u.Host, Resource: "acct:a@localhost", ValidationMode: webfinger.ValidationStrict},
+	}, nil)
+
+	if results[0].Err != nil {
+		t.Error(results[0].Err)
+	}
+
+	var validationError *webfinger.ValidationError
+	if !errors.As(results[1].Err, &validationError) {
+		t.Error(results[1].Err)
+	}
+}
@@ -0,0 +1,321 @@
+package webfinger
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultLRUCacheCapacity = 1024
+
+var errNotModified = errors.New("not modified")
+
+type CacheEntry struct {
+	Message      *Message
+	NotFound     bool
+	ExpiresAt    time.Time
+	URL          string
+	StatusCode   int
+	Header       http.Header
+	ETag         string
+	LastModified string
+}
+
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+type LRUCache struct {
+	capacity int
+	mutex    sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type lruCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = DefaultLRUCacheCapacity
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*lruCacheItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry *CacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruCacheItem).entry = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruCacheItem{
+		key:   key,
+		entry: entry,
+	})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruCacheItem).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+}
+
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (client *Client) lookupWithCache(ctx context.Context, webFingerRequest *Request) (*LookupResult, error) {
+	key := cacheKey(client.HTTPMode, webFingerRequest)
+	now := time.Now()
+
+	entry, ok := client.Cache.Get(key)
+	if ok && now.Before(entry.ExpiresAt) {
+		client.observer().CacheHit(ctx, CacheHitEvent{
+			Host:     webFingerRequest.Host,
+			NotFound: entry.NotFound,
+		})
+
+		if entry.NotFound {
+			return nil, ErrResourceNotFound
+		}
+
+		return entry.result(), nil
+	}
+
+	if ok && !entry.NotFound && (entry.ETag != "" || entry.LastModified != "") {
+		result, err := client.revalidate(ctx, key, entry)
+		if err == nil && result.Revalidated {
+			client.observer().CacheHit(ctx, CacheHitEvent{
+				Host:        webFingerRequest.Host,
+				Revalidated: true,
+			})
+		}
+
+		if !errors.Is(err, errNotModified) {
+			return result, err
+		}
+	}
+
+	result, err := client.lookup(ctx, webFingerRequest)
+	if err != nil {
+		if IsNotFound(err) && client.NegativeCacheTTL > 0 {
+			client.Cache.Set(key, &CacheEntry{
+				NotFound:  true,
+				ExpiresAt: now.Add(client.NegativeCacheTTL),
+			})
+		}
+
+		return nil, err
+	}
+
+	client.storeCacheEntry(key, result, now)
+
+	return result, nil
+}
+
+func (client *Client) revalidate(ctx context.Context, key string, entry *CacheEntry) (*LookupResult, error) {
+	request, err := client.newHTTPRequest(ctx, entry.URL, "application/jrd+json, application/xrd+xml")
+	if err != nil {
+		return nil, errNotModified
+	}
+
+	if entry.ETag != "" {
+		request.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	if entry.LastModified != "" {
+		request.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	now := time.Now()
+	webFingerMessage, response, err := client.fetchMessage(ctx, request)
+	switch {
+	case errors.Is(err, errNotModified):
+		result := entry.result()
+		result.Revalidated = true
+
+		header := http.Header{}
+		if response != nil {
+			header = response.Header
+		}
+
+		expiresAt, store := cacheExpiration(header, entry.Message, now)
+		if !store {
+			client.Cache.Delete(key)
+			return result, nil
+		}
+
+		client.Cache.Set(key, &CacheEntry{
+			Message:      entry.Message,
+			ExpiresAt:    expiresAt,
+			URL:          entry.URL,
+			StatusCode:   entry.StatusCode,
+			Header:       entry.Header,
+			ETag:         firstNonEmpty(header.Get("ETag"), entry.ETag),
+			LastModified: firstNonEmpty(header.Get("Last-Modified"), entry.LastModified),
+		})
+
+		return result, nil
+
+	case err != nil:
+		if ctx.Err() == nil && !IsNotFound(err) {
+			return nil, errNotModified
+		}
+
+		client.Cache.Delete(key)
+		return nil, err
+	}
+
+	result := newLookupResult(webFingerMessage, response)
+	client.storeCacheEntry(key, result, now)
+
+	return result, nil
+}
+
+func (client *Client) storeCacheEntry(key string, result *LookupResult, now time.Time) {
+	if result.Header == nil {
+		return
+	}
+
+	expiresAt, store := cacheExpiration(result.Header, result.Message, now)
+	if !store {
+		client.Cache.Delete(key)
+		return
+	}
+
+	client.Cache.Set(key, &CacheEntry{
+		Message:      result.Message.clone(),
+		ExpiresAt:    expiresAt,
+		URL:          result.URL,
+		StatusCode:   result.StatusCode,
+		Header:       result.Header.Clone(),
+		ETag:         result.Header.Get("ETag"),
+		LastModified: result.Header.Get("Last-Modified"),
+	})
+}
+
+func (entry *CacheEntry) result() *LookupResult {
+	return &LookupResult{
+		Message:     entry.Message.clone(),
+		URL:         entry.URL,
+		StatusCode:  entry.StatusCode,
+		Header:      entry.Header.Clone(),
+		ContentType: contentType(entry.Header),
+		FromCache:   true,
+	}
+}
+
+func cacheKey(httpMode bool, webFingerRequest *Request) string {
+	rels := slices.Clone(webFingerRequest.Rels)
+	slices.Sort(rels)
+
+	return getSchema(httpMode) + "//" + strings.ToLower(webFingerRequest.Host) + "\n" + webFingerRequest.Resource + "\n" + strings.Join(slices.Compact(rels), "\n")
+}
+
+func cacheExpiration(header http.Header, webFingerMessage *Message, now time.Time) (time.Time, bool) {
+	validator := header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+
+	maxAge, hasMaxAge := -1, false
+	for _, directive := range strings.Split(strings.Join(header.Values("Cache-Control"), ","), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			return time.Time{}, false
+		case "no-cache":
+			return now, validator
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge, hasMaxAge = seconds, true
+			}
+		}
+	}
+
+	if hasMaxAge {
+		if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+			maxAge -= age
+		}
+		if maxAge > 0 {
+			return now.Add(time.Duration(maxAge) * time.Second), true
+		}
+		return now, validator
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return now, validator
+		}
+
+		if date, err := http.ParseTime(header.Get("Date")); err == nil {
+			expiresAt = now.Add(expiresAt.Sub(date))
+		}
+
+		if !expiresAt.After(now) {
+			return now, validator
+		}
+
+		return expiresAt, true
+	}
+
+	if webFingerMessage != nil && webFingerMessage.Expires != nil && webFingerMessage.Expires.After(now) {
+		return *webFingerMessage.Expires, true
+	}
+
+	return now, validator
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,196 @@
+package webfinger_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_LRUCache_Eviction(t *testing.T) {
+	cache := webfinger.NewLRUCache(2)
+
+	cache.Set("a", &webfinger.CacheEntry{NotFound: true})
+	cache.Set("b", &webfinger.CacheEntry{NotFound: true})
+
+	if _, ok := cache.Get("a"); !ok {
+		t.FailNow()
+	}
+
+	cache.Set("c", &webfinger.CacheEntry{NotFound: true})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+
+	cache.Delete("a")
+
+	if cache.Len() != 1 {
+		t.Errorf("unexpected length: %d", cache.Len())
+	}
+}
+
+func Test_Client_Do_Cache(t *testing.T) {
+	tests := []struct {
+		Header        map[string]string
+		Body          string
+		ExpectedCount int32
+	}{
+		{
+			Header:        map[string]string{"Cache-Control": "public, max-age=60"},
+			ExpectedCount: 1,
+		},
+		{
+			Header:        map[string]string{"Cache-Control": "no-store, max-age=60"},
+			ExpectedCount: 2,
+		},
+		{
+			Header:        map[string]string{"Expires": time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)},
+			ExpectedCount: 1,
+		},
+		{
+			Header:        map[string]string{"Cache-Control": "max-age=60", "Age": "60"},
+			ExpectedCount: 2,
+		},
+		{
+			Header:        map[string]string{},
+			ExpectedCount: 2,
+		},
+	}
+
+	for i, test := range tests {
+		testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+			for k, v := range test.Header {
+				w.Header().Set(k, v)
+			}
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:test@`+r.Host+`"}`)
+		})
+
+		client := &webfinger.Client{
+			HTTPClient: http.DefaultClient,
+			HTTPMode:   true,
+			Cache:      webfinger.NewLRUCache(0),
+		}
+
+		for range 2 {
+			message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if message.Subject != "acct:test@"+host {
+				t.FailNow()
+			}
+		}
+
+		if count.Load() != test.ExpectedCount {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, test.ExpectedCount, count.Load())
+			t.Fail()
+		}
+
+		testServer.Close()
+	}
+}
+
+func Test_Client_Do_Cache_XRDExpires(t *testing.T) {
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		w.Header().Set("Content-Type", "application/xrd+xml")
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0">
+<Expires>`+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`</Expires>
+<Subject>acct:test@`+r.Host+`</Subject>
+</XRD>`)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		Cache:      webfinger.NewLRUCache(0),
+	}
+
+	for range 2 {
+		if _, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count.Load() != 1 {
+		t.Errorf("unexpected request count: %d", count.Load())
+	}
+}
+
+func Test_Client_Do_Cache_Revalidation(t *testing.T) {
+	var notModified atomic.Int32
+
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@`+r.Host+`"}`)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		Cache:      webfinger.NewLRUCache(0),
+	}
+
+	for range 3 {
+		message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if message.Subject != "acct:test@"+host {
+			t.FailNow()
+		}
+
+		message.Subject = "modified"
+	}
+
+	if count.Load() != 3 || notModified.Load() != 2 {
+		t.Errorf("unexpected request count: %d, %d", count.Load(), notModified.Load())
+	}
+}
+
+func Test_Client_Do_Cache_NegativeTTL(t *testing.T) {
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		Cache:            webfinger.NewLRUCache(0),
+		NegativeCacheTTL: time.Minute,
+	}
+
+	for range 2 {
+		if _, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host}); !errors.Is(err, webfinger.ErrResourceNotFound) {
+			t.Fatal(err)
+		}
+	}
+
+	if count.Load() != 1 {
+		t.Errorf("unexpected request count: %d", count.Load())
+	}
+}
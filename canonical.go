@@ -0,0 +1,81 @@
+package webfinger
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+const DefaultMaxCanonicalHops = 3
+
+var (
+	ErrCanonicalLoop        = errors.New("domains point at each other")
+	ErrTooManyCanonicalHops = errors.New("too many canonical hops")
+	ErrNotAccountSubject    = errors.New("subject is not an acct URI")
+)
+
+func (client *Client) ResolveCanonicalHandle(ctx context.Context, handle string) (string, error) {
+	resource, host, err := ParseResource(handle)
+	if err != nil {
+		return "", &Error{
+			Err: err,
+		}
+	}
+
+	chain := make([]string, 0, DefaultMaxCanonicalHops+1)
+	for {
+		if slices.Contains(chain, resource) {
+			return "", canonicalError(ErrCanonicalLoop, append(chain, resource))
+		}
+
+		if len(chain) >= DefaultMaxCanonicalHops {
+			return "", canonicalError(ErrTooManyCanonicalHops, append(chain, resource))
+		}
+		chain = append(chain, resource)
+
+		webFingerRequest := &Request{
+			Host:     host,
+			Resource: resource,
+		}
+
+		result, err := client.resolve(ctx, webFingerRequest, false)
+		if err != nil {
+			return "", err
+		}
+
+		if !strings.HasPrefix(strings.ToLower(result.Message.Subject), "acct:") {
+			return "", canonicalError(ErrNotAccountSubject, chain)
+		}
+
+		subject, subjectHost, err := ParseResource(result.Message.Subject)
+		if err != nil {
+			return "", canonicalError(err, chain)
+		}
+
+		if subject == resource {
+			if err := client.validate(ctx, webFingerRequest, result, result.Message.resourceViolations(resource)); err != nil {
+				return "", err
+			}
+
+			if client.VerifyBinding {
+				if err := client.verifyBinding(ctx, webFingerRequest, result); err != nil {
+					return "", err
+				}
+			}
+
+			return subject, nil
+		}
+
+		resource, host = subject, subjectHost
+	}
+}
+
+func canonicalError(err error, chain []string) error {
+	return &Error{
+		Err: &CanonicalError{
+			Err:   err,
+			Chain: chain,
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_ResolveCanonicalHandle(t *testing.T) {
+	var localHost, webHost string
+	var webServerURL string
+
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger" && r.URL.Query().Get("resource") == "acct:loop@"+localHost:
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:loop@`+webHost+`"}`)
+		case r.URL.Path == "/.well-known/webfinger":
+			http.Redirect(w, r, webServerURL+r.URL.RequestURI(), http.StatusMovedPermanently)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer localServer.Close()
+
+	webServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			resource := r.URL.Query().Get("resource")
+			switch resource {
+			case "acct:test@" + localHost, "acct:test@" + webHost:
+				w.Header().Set("Content-Type", "application/jrd+json")
+				io.WriteString(w, `{"subject":"acct:test@`+localHost+`"}`)
+			case "acct:loop@" + webHost:
+				w.Header().Set("Content-Type", "application/jrd+json")
+				io.WriteString(w, `{"subject":"acct:loop@`+localHost+`"}`)
+			case "acct:url@" + webHost:
+				w.Header().Set("Content-Type", "application/jrd+json")
+				io.WriteString(w, `{"subject":"https://`+webHost+`/users/url"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer webServer.Close()
+
+	webServerURL = webServer.URL
+
+	u, err := url.Parse(localServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+	localHost = u.Host
+
+	u, err = url.Parse(webServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+	webHost = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	verifyingClient := &webfinger.Client{
+		HTTPClient:     http.DefaultClient,
+		HTTPMode:       true,
+		VerifyBinding:  true,
+		ValidationMode: webfinger.ValidationStrict,
+	}
+
+	for _, c := range []*webfinger.Client{client, verifyingClient} {
+		for _, handle := range []string{"@test@" + localHost, "acct:test@" + webHost} {
+			subject, err := c.ResolveCanonicalHandle(context.Background(), handle)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if subject != "acct:test@"+localHost {
+				t.Errorf("unexpected subject: %s", subject)
+			}
+		}
+	}
+
+	tests := []struct {
+		Handle   string
+		Expected error
+	}{
+		{Handle: "acct:loop@" + webHost, Expected: webfinger.ErrCanonicalLoop},
+		{Handle: "acct:url@" + webHost, Expected: webfinger.ErrNotAccountSubject},
+		{Handle: "acct:notfound@" + webHost, Expected: webfinger.ErrResourceNotFound},
+	}
+
+	for i, test := range tests {
+		_, err := client.ResolveCanonicalHandle(context.Background(), test.Handle)
+		if !errors.Is(err, test.Expected) {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+		}
+	}
+}
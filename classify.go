@@ -0,0 +1,109 @@
+package webfinger
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+)
+
+func IsNotFound(err error) bool {
+	if errors.Is(err, ErrResourceNotFound) {
+		return true
+	}
+
+	var statusError *WebFingerResponseStatusError
+	return errors.As(err, &statusError) && (statusError.StatusCode == http.StatusNotFound || statusError.StatusCode == http.StatusGone)
+}
+
+// IsTemporary reports whether repeating the lookup later may succeed.
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || IsNotFound(err) {
+		return false
+	}
+
+	var statusError *WebFingerResponseStatusError
+	var rateLimitedError *RateLimitedError
+	var dnsError *net.DNSError
+	var timeoutError *TimeoutError
+	var transportError *TransportError
+	var policyError *PolicyError
+	var decodeError *DecodeError
+	var tlsError *TLSError
+
+	switch {
+	case errors.As(err, &policyError), errors.As(err, &decodeError), errors.As(err, &tlsError):
+		return false
+	case errors.As(err, &statusError):
+		return statusError.StatusCode == http.StatusTooManyRequests || statusError.StatusCode >= 500
+	case errors.As(err, &rateLimitedError), errors.As(err, &timeoutError):
+		return true
+	case errors.As(err, &dnsError):
+		return dnsError.IsTimeout || dnsError.IsTemporary
+	case errors.As(err, &transportError):
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsPermanent reports whether repeating the lookup will fail the same way.
+// Cancelled lookups are neither temporary nor permanent.
+func IsPermanent(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled) && !IsTemporary(err)
+}
+
+func newTransportError(ctx context.Context, request *http.Request, err error) error {
+	host := request.URL.Host
+	requestURL := request.URL.String()
+	err = contextError(ctx, err)
+
+	var forbiddenDestinationError *ForbiddenDestinationError
+	var redirectError *RedirectError
+	var responseTooLargeError *ResponseTooLargeError
+	var dnsError *net.DNSError
+	var netError net.Error
+
+	switch {
+	case errors.As(err, &forbiddenDestinationError), errors.As(err, &redirectError), errors.As(err, &responseTooLargeError):
+		return &PolicyError{Host: host, URL: requestURL, Err: err}
+	case errors.As(err, &dnsError):
+		return &DNSError{Host: host, URL: requestURL, Err: err}
+	case isTLSError(err):
+		return &TLSError{Host: host, URL: requestURL, Err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netError) && netError.Timeout():
+		return &TimeoutError{Host: host, URL: requestURL, Err: err}
+	}
+
+	return &TransportError{Host: host, URL: requestURL, Err: err}
+}
+
+func newDecodeError(response *http.Response, contentType string, err error) error {
+	host := response.Request.URL.Host
+	requestURL := response.Request.URL.String()
+
+	var decodeLimitError *DecodeLimitError
+	if errors.As(err, &decodeLimitError) {
+		return &PolicyError{Host: host, URL: requestURL, Err: err}
+	}
+
+	return &DecodeError{Host: host, URL: requestURL, ContentType: contentType, Err: err}
+}
+
+func isTLSError(err error) bool {
+	var recordHeaderError tls.RecordHeaderError
+	var alertError tls.AlertError
+	var certificateVerificationError *tls.CertificateVerificationError
+	var unknownAuthorityError x509.UnknownAuthorityError
+	var hostnameError x509.HostnameError
+	var certificateInvalidError x509.CertificateInvalidError
+
+	return errors.As(err, &recordHeaderError) ||
+		errors.As(err, &alertError) ||
+		errors.As(err, &certificateVerificationError) ||
+		errors.As(err, &unknownAuthorityError) ||
+		errors.As(err, &hostnameError) ||
+		errors.As(err, &certificateInvalidError)
+}
@@ -0,0 +1,163 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_IsTemporary_IsPermanent_IsNotFound(t *testing.T) {
+	tests := []struct {
+		Err       error
+		Temporary bool
+		Permanent bool
+		NotFound  bool
+	}{
+		{Err: nil},
+		{Err: webfinger.ErrResourceNotFound, Permanent: true, NotFound: true},
+		{Err: &webfinger.Error{Err: &webfinger.WebFingerResponseStatusError{StatusCode: http.StatusGone}}, Permanent: true, NotFound: true},
+		{Err: &webfinger.Error{Err: &webfinger.WebFingerResponseStatusError{StatusCode: http.StatusBadRequest}}, Permanent: true},
+		{Err: &webfinger.Error{Err: &webfinger.WebFingerResponseStatusError{StatusCode: http.StatusTooManyRequests}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.WebFingerResponseStatusError{StatusCode: http.StatusServiceUnavailable}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.RetryError{Attempts: 3, Err: &webfinger.WebFingerResponseStatusError{StatusCode: http.StatusBadGateway}}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.RateLimitedError{Host: "example.com"}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.TransportError{Err: errors.New("connection reset")}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.TimeoutError{Err: context.DeadlineExceeded}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.DNSError{Err: &net.DNSError{IsNotFound: true}}}, Permanent: true},
+		{Err: &webfinger.Error{Err: &webfinger.DNSError{Err: &net.DNSError{IsTimeout: true}}}, Temporary: true},
+		{Err: &webfinger.Error{Err: &webfinger.TLSError{Err: errors.New("bad certificate")}}, Permanent: true},
+		{Err: &webfinger.Error{Err: &webfinger.DecodeError{Err: errors.New("invalid character")}}, Permanent: true},
+		{Err: &webfinger.Error{Err: &webfinger.PolicyError{Err: &webfinger.ResponseTooLargeError{Limit: 1}}}, Permanent: true},
+		{Err: &webfinger.Error{Err: &webfinger.TransportError{Err: context.Canceled}}},
+	}
+
+	for i, test := range tests {
+		if actual := webfinger.IsTemporary(test.Err); test.Temporary != actual {
+			t.Logf("case_index: %d, IsTemporary expected: %v, actual: %v", i, test.Temporary, actual)
+			t.Fail()
+		}
+
+		if actual := webfinger.IsPermanent(test.Err); test.Permanent != actual {
+			t.Logf("case_index: %d, IsPermanent expected: %v, actual: %v", i, test.Permanent, actual)
+			t.Fail()
+		}
+
+		if actual := webfinger.IsNotFound(test.Err); test.NotFound != actual {
+			t.Logf("case_index: %d, IsNotFound expected: %v, actual: %v", i, test.NotFound, actual)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_ErrorTaxonomy(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resource") {
+		case "acct:slow@example.com":
+			time.Sleep(200 * time.Millisecond)
+		case "acct:broken@example.com":
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":`)
+		case "acct:large@example.com":
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:large@example.com","aliases":["https://example.com/users/large"]}`)
+		}
+	})
+
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	testTLSServer := httptest.NewTLSServer(handler)
+	defer testTLSServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	tlsURL, err := url.Parse(testTLSServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		client := &webfinger.Client{HTTPClient: http.DefaultClient, HTTPMode: true}
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:broken@example.com"})
+
+		var decodeError *webfinger.DecodeError
+		if !errors.As(err, &decodeError) || decodeError.Host != u.Host || decodeError.ContentType != "application/jrd+json" || decodeError.URL == "" {
+			t.Error(err)
+		}
+
+		if !webfinger.IsPermanent(err) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		client := &webfinger.Client{HTTPClient: http.DefaultClient, HTTPMode: true, MaxResponseSize: 16}
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:large@example.com"})
+
+		var policyError *webfinger.PolicyError
+		var responseTooLargeError *webfinger.ResponseTooLargeError
+		if !errors.As(err, &policyError) || policyError.Host != u.Host || !errors.As(err, &responseTooLargeError) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := &webfinger.Client{HTTPClient: &http.Client{Timeout: 50 * time.Millisecond}, HTTPMode: true}
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:slow@example.com"})
+
+		var timeoutError *webfinger.TimeoutError
+		if !errors.As(err, &timeoutError) || timeoutError.Host != u.Host {
+			t.Error(err)
+		}
+
+		if !webfinger.IsTemporary(err) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		client := &webfinger.Client{HTTPClient: &http.Client{}}
+		_, err := client.Do(&webfinger.Request{Host: tlsURL.Host, Resource: "acct:test@example.com"})
+
+		var tlsError *webfinger.TLSError
+		if !errors.As(err, &tlsError) || tlsError.Host != tlsURL.Host {
+			t.Error(err)
+		}
+
+		if !webfinger.IsPermanent(err) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("transport", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		host := listener.Addr().String()
+		listener.Close()
+
+		client := &webfinger.Client{HTTPClient: http.DefaultClient, HTTPMode: true}
+		_, err = client.Do(&webfinger.Request{Host: host, Resource: "acct:test@example.com"})
+
+		var transportError *webfinger.TransportError
+		if !errors.As(err, &transportError) || transportError.Host != host {
+			t.Error(err)
+		}
+
+		if !webfinger.IsTemporary(err) {
+			t.Error(err)
+		}
+	})
+}
@@ -1,12 +1,17 @@
 package webfinger
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"mime"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 var DefaultClient = &Client{
@@ -23,77 +28,447 @@ type Client struct {
 	UserAgent            string
 	HTTPMode             bool
 	AdditionalMediaTypes *AdditionalMediaTypes
+	FilterLinksByRels    bool
+	HostMetaFallback     bool
+	Cache                Cache
+	NegativeCacheTTL     time.Duration
+	MaxResponseSize      int64
+	MaxErrorBodySize     int64
+	DecodeLimits         *DecodeLimits
+	SafeDialer           *SafeDialer
+	RetryPolicy          *RetryPolicy
+	Limiter              Limiter
+	RateLimitFailFast    bool
+	CoalesceRequests     bool
+	RedirectPolicy       *RedirectPolicy
+	ValidationMode       ValidationMode
+	VerifyBinding        bool
+	Middleware           []Middleware
+	Observer             Observer
+	Logger               *slog.Logger
+	RedactResource       func(resource string) string
+
+	flights flightGroup
 }
 
 func (client *Client) Do(webFingerRequest *Request) (*Message, error) {
-	request, err := client.createHTTPRequest(client.HTTPMode, webFingerRequest)
+	return client.DoContext(context.Background(), webFingerRequest)
+}
+
+func (client *Client) DoContext(ctx context.Context, webFingerRequest *Request) (*Message, error) {
+	result, err := client.DoResult(ctx, webFingerRequest)
 	if err != nil {
-		return nil, &Error{
-			Err: err,
+		return nil, err
+	}
+
+	return result.Message, nil
+}
+
+func (client *Client) DoResult(ctx context.Context, webFingerRequest *Request) (*LookupResult, error) {
+	return client.resolve(ctx, webFingerRequest, true)
+}
+
+// resolve skips the checks that bind the response to the requested resource
+// when bind is false, for callers that confirm the binding themselves.
+func (client *Client) resolve(ctx context.Context, webFingerRequest *Request, bind bool) (*LookupResult, error) {
+	observer := client.observer()
+	observer.LookupStart(ctx, LookupStartEvent{
+		Host: webFingerRequest.Host,
+	})
+
+	start := time.Now()
+	result, err := client.doResult(ctx, webFingerRequest, start, bind)
+	observer.LookupEnd(ctx, lookupEndEvent(webFingerRequest.Host, result, err, time.Since(start)))
+
+	return result, err
+}
+
+func (client *Client) doResult(ctx context.Context, webFingerRequest *Request, start time.Time, bind bool) (*LookupResult, error) {
+	fetch := func(ctx context.Context) (*LookupResult, error) {
+		if client.Cache != nil {
+			return client.lookupWithCache(ctx, webFingerRequest)
+		}
+
+		return client.lookup(ctx, webFingerRequest)
+	}
+
+	var result *LookupResult
+	var err error
+	if client.CoalesceRequests {
+		result, err = client.flights.do(ctx, cacheKey(client.HTTPMode, webFingerRequest), fetch)
+	} else {
+		result, err = fetch(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Elapsed = time.Since(start)
+
+	violations := result.Message.violations()
+	if bind {
+		violations = append(violations, result.Message.resourceViolations(webFingerRequest.Resource)...)
+	}
+
+	if err := client.validate(ctx, webFingerRequest, result, violations); err != nil {
+		return nil, err
+	}
+
+	if bind && client.VerifyBinding {
+		if err := client.verifyBinding(ctx, webFingerRequest, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if client.FilterLinksByRels && len(webFingerRequest.Rels) > 0 {
+		result.Message.Links = result.Message.GetLinksByRelationTypes(webFingerRequest.Rels...)
+	}
+
+	return result, nil
+}
+
+func (client *Client) validate(ctx context.Context, webFingerRequest *Request, result *LookupResult, violations []Violation) error {
+	mode := webFingerRequest.ValidationMode
+	if mode == ValidationDefault {
+		mode = client.ValidationMode
+	}
+
+	if mode == ValidationDefault || mode == ValidationNone {
+		return nil
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if mode == ValidationStrict {
+		for _, violation := range violations {
+			client.log(ctx, slog.LevelWarn, "webfinger protocol violation",
+				slog.String("host", webFingerRequest.Host),
+				slog.String("field", violation.Field),
+				slog.String("description", client.redact(violation.Description, webFingerRequest.Resource)))
+		}
+
+		return &Error{
+			Err: &PolicyError{
+				Host: resultHost(webFingerRequest, result),
+				URL:  result.URL,
+				Err: &ValidationError{
+					Violations: violations,
+				},
+			},
 		}
 	}
 
-	response, err := client.HTTPClient.Do(request)
+	for _, violation := range violations {
+		client.log(ctx, slog.LevelDebug, "webfinger validation warning",
+			slog.String("host", webFingerRequest.Host),
+			slog.String("field", violation.Field),
+			slog.String("description", client.redact(violation.Description, webFingerRequest.Resource)))
+	}
+
+	result.Warnings = append(result.Warnings, violations...)
+
+	return nil
+}
+
+func (client *Client) lookup(ctx context.Context, webFingerRequest *Request) (*LookupResult, error) {
+	request, err := client.createHTTPRequest(ctx, client.HTTPMode, webFingerRequest)
 	if err != nil {
 		return nil, &Error{
 			Err: err,
 		}
 	}
+
+	client.log(ctx, slog.LevelDebug, "webfinger request",
+		slog.String("host", webFingerRequest.Host),
+		slog.String("url", client.redact(request.URL.String(), webFingerRequest.Resource)))
+
+	webFingerMessage, response, err := client.fetchMessage(ctx, request)
+	client.logResponse(ctx, webFingerRequest.Resource, response)
+	if err != nil && client.HostMetaFallback && isHostMetaFallbackError(err) {
+		client.log(ctx, slog.LevelDebug, "webfinger host-meta fallback",
+			slog.String("host", webFingerRequest.Host),
+			slog.String("outcome", string(outcomeOf(err))))
+
+		webFingerMessage, response, err = client.doHostMetaFallback(ctx, webFingerRequest, err)
+		client.logResponse(ctx, webFingerRequest.Resource, response)
+		if err == nil {
+			result := newLookupResult(webFingerMessage, response)
+			result.HostMetaFallback = true
+			return result, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newLookupResult(webFingerMessage, response), nil
+}
+
+func (client *Client) fetchMessage(ctx context.Context, request *http.Request) (*Message, *http.Response, error) {
+	if client.RetryPolicy != nil {
+		return client.fetchMessageWithRetry(ctx, request)
+	}
+
+	return client.fetchMessageOnce(request)
+}
+
+func (client *Client) fetchMessageOnce(request *http.Request) (*Message, *http.Response, error) {
+	webFingerMessage, response, err := client.doer().Do(request)
+	if err == nil && webFingerMessage == nil {
+		return nil, response, &Error{
+			Err: ErrInvalidResponse,
+		}
+	}
+
+	return webFingerMessage, response, err
+}
+
+func (client *Client) exchange(request *http.Request) (*Message, *http.Response, error) {
+	ctx := request.Context()
+
+	response, err := client.send(ctx, request)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotModified {
+		return nil, response, errNotModified
+	}
+
 	if err := client.statusCodeToError(response); err != nil {
-		return nil, err
+		return nil, response, err
 	}
 
-	mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	webFingerMessage, err := client.decodeResponse(ctx, response)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return webFingerMessage, response, nil
+}
+
+func (client *Client) send(ctx context.Context, request *http.Request) (*http.Response, error) {
+	httpClient, err := client.httpClient()
 	if err != nil {
 		return nil, &Error{
 			Err: err,
 		}
 	}
 
+	var release func()
+	if client.Limiter != nil {
+		release, err = client.Limiter.Acquire(ctx, request.URL.Host, !client.RateLimitFailFast)
+		if err != nil {
+			return nil, &Error{
+				Err: contextError(ctx, err),
+			}
+		}
+	}
+
+	response, err := httpClient.Do(client.withClientTrace(request))
+	if err != nil {
+		if release != nil {
+			release()
+		}
+
+		return nil, &Error{
+			Err: newTransportError(ctx, request, err),
+		}
+	}
+
+	if release != nil {
+		response.Body = &releaseOnClose{
+			ReadCloser: response.Body,
+			release:    release,
+		}
+	}
+
+	return response, nil
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+
+	return err
+}
+
+func (client *Client) httpClient() (*http.Client, error) {
+	httpClient := client.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if client.SafeDialer != nil {
+		var err error
+		httpClient, err = client.SafeDialer.HTTPClient(httpClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if client.RedirectPolicy != nil {
+		httpClient = client.RedirectPolicy.apply(httpClient)
+	} else {
+		httpClient = refuseDowngrade(httpClient)
+	}
+
+	return httpClient, nil
+}
+
+func (client *Client) decodeResponse(ctx context.Context, response *http.Response) (*Message, error) {
+	mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if err != nil {
+		client.decodeFailure(ctx, response, "", err)
+
+		return nil, &Error{
+			Err: newDecodeError(response, response.Header.Get("Content-Type"), err),
+		}
+	}
+
 	additionalMediaTypes := client.AdditionalMediaTypes
 	if client.AdditionalMediaTypes == nil {
 		additionalMediaTypes = &AdditionalMediaTypes{}
 	}
 
+	limits := client.DecodeLimits
+	if limits == nil {
+		limits = &DefaultDecodeLimits
+	}
+
 	var webFingerMessage Message
+	var unmarshal func([]byte) error
 	switch {
 	case isXML(mediaType, additionalMediaTypes.XML):
-		b, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, err
+		unmarshal = func(b []byte) error {
+			return xml.Unmarshal(b, &limitedMessage{
+				message: &webFingerMessage,
+				limits:  limits,
+			})
 		}
-
-		if err := xml.Unmarshal(b, &webFingerMessage); err != nil {
-			return nil, &Error{
-				Err: err,
+	case isJSON(mediaType, additionalMediaTypes.JSON):
+		unmarshal = func(b []byte) error {
+			if err := json.Unmarshal(b, &webFingerMessage); err != nil {
+				return err
 			}
+			return limits.checkMessage(&webFingerMessage)
 		}
+	default:
+		err := &UnsupportedContentTypeError{
+			ContentType: mediaType,
+		}
+		client.decodeFailure(ctx, response, mediaType, err)
 
-		return &webFingerMessage, nil
+		return nil, &Error{
+			Err: newDecodeError(response, mediaType, err),
+		}
+	}
 
-	case isJSON(mediaType, additionalMediaTypes.JSON):
-		b, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, err
+	b, err := client.readBody(response)
+	if err != nil {
+		return nil, &Error{
+			Err: newTransportError(ctx, response.Request, err),
 		}
+	}
 
-		if err := json.Unmarshal(b, &webFingerMessage); err != nil {
-			return nil, &Error{
-				Err: err,
-			}
+	if err := ctx.Err(); err != nil {
+		return nil, &Error{
+			Err: err,
 		}
+	}
 
-		return &webFingerMessage, nil
+	if err := unmarshal(b); err != nil {
+		client.decodeFailure(ctx, response, mediaType, err)
 
-	default:
 		return nil, &Error{
-			Err: &UnsupportedContentTypeError{
-				ContentType: mediaType,
-			},
+			Err: newDecodeError(response, mediaType, err),
+		}
+	}
+
+	return &webFingerMessage, nil
+}
+
+func (client *Client) decodeFailure(ctx context.Context, response *http.Response, mediaType string, err error) {
+	client.observer().DecodeFailure(ctx, DecodeFailureEvent{
+		Host:        response.Request.URL.Host,
+		ContentType: mediaType,
+	})
+
+	client.log(ctx, slog.LevelWarn, "webfinger protocol violation",
+		slog.String("host", response.Request.URL.Host),
+		slog.String("content_type", mediaType),
+		slog.String("error", err.Error()))
+}
+
+func (client *Client) logResponse(ctx context.Context, resource string, response *http.Response) {
+	if response == nil || response.Request == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("url", client.redact(response.Request.URL.String(), resource)),
+		slog.Int("status", response.StatusCode),
+		slog.String("content_type", contentType(response.Header)),
+	}
+
+	if chain := redirectChain(response); len(chain) > 0 {
+		for i := range chain {
+			chain[i] = client.redact(chain[i], resource)
 		}
+		attrs = append(attrs, slog.Any("redirects", chain))
 	}
+
+	client.log(ctx, slog.LevelDebug, "webfinger response", attrs...)
+}
+
+func (client *Client) readBody(response *http.Response) ([]byte, error) {
+	limit := client.MaxResponseSize
+	if limit == 0 {
+		limit = DefaultMaxResponseSize
+	}
+
+	if limit < 0 {
+		return io.ReadAll(response.Body)
+	}
+
+	if response.ContentLength > limit {
+		return nil, &ResponseTooLargeError{
+			Limit: limit,
+		}
+	}
+
+	b, err := io.ReadAll(io.LimitReader(response.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(b)) > limit {
+		return nil, &ResponseTooLargeError{
+			Limit: limit,
+		}
+	}
+
+	return b, nil
+}
+
+func contextError(ctx context.Context, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || errors.Is(err, ctxErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ctxErr, err)
 }
 
 func isXML(mediaType string, AdditionalMediaTypes []string) bool {
@@ -124,7 +499,7 @@ func isJSON(mediaType string, AdditionalMediaTypes []string) bool {
 	return false
 }
 
-func (client *Client) createHTTPRequest(httpMode bool, webFingerRequest *Request) (*http.Request, error) {
+func (client *Client) createHTTPRequest(ctx context.Context, httpMode bool, webFingerRequest *Request) (*http.Request, error) {
 	// requestURL := ?resource=" + url.QueryEscape()
 	requestURL, err := url.Parse(getSchema(httpMode) + "//" + webFingerRequest.Host + "/.well-known/webfinger")
 	if err != nil {
@@ -133,14 +508,21 @@ func (client *Client) createHTTPRequest(httpMode bool, webFingerRequest *Request
 
 	queries := requestURL.Query()
 	queries.Set("resource", webFingerRequest.Resource)
+	for _, rel := range webFingerRequest.Rels {
+		queries.Add("rel", rel)
+	}
 	requestURL.RawQuery = queries.Encode()
 
-	request, err := http.NewRequest("GET", requestURL.String(), nil)
+	return client.newHTTPRequest(ctx, requestURL.String(), "application/jrd+json, application/xrd+xml")
+}
+
+func (client *Client) newHTTPRequest(ctx context.Context, requestURL string, accept string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	request.Header.Set("Accept", "application/jrd+json, application/xrd+xml")
+	request.Header.Set("Accept", accept)
 
 	if client.UserAgent != "" {
 		request.Header.Set("User-Agent", client.UserAgent)
@@ -156,11 +538,7 @@ func (client *Client) statusCodeToError(response *http.Response) error {
 			return ErrResourceNotFound
 		default:
 			return &Error{
-				Err: &WebFingerResponseStatusError{
-					URL:        response.Request.URL.String(),
-					StatusCode: response.StatusCode,
-					Status:     response.Status,
-				},
+				Err: client.newStatusError(response),
 			}
 		}
 	}
@@ -1,16 +1,40 @@
 package webfinger_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	webfinger "github.com/MitarashiDango/go-webfinger"
 )
 
+func newWebFingerTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, attempt int32)) (*httptest.Server, string, *atomic.Int32) {
+	var count atomic.Int32
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			handler(w, r, count.Add(1))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	return testServer, u.Host, &count
+}
+
 func Test_Client_Do_JSONResponse(t *testing.T) {
 
 	var baseURL string
@@ -440,6 +464,288 @@ func Test_Client_Do_InvalidContentType(t *testing.T) {
 	}
 }
 
+func Test_Client_DoContext_Canceled(t *testing.T) {
+	var host string
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			cancel()
+			<-r.Context().Done()
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	message, err := client.DoContext(ctx, &webfinger.Request{Host: host, Resource: "acct:test@" + host})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var webFingerError *webfinger.Error
+	if !errors.As(err, &webFingerError) {
+		t.Error(err)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+
+	if message != nil {
+		t.FailNow()
+	}
+}
+
+func Test_Client_DoContext_DeadlineExceeded(t *testing.T) {
+	var host string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			w.Header().Set("Content-Type", "application/jrd+json")
+			w.WriteHeader(200)
+			io.WriteString(w, `{"subject":`)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	message, err := client.DoContext(ctx, &webfinger.Request{Host: host, Resource: "acct:test@" + host})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var webFingerError *webfinger.Error
+	if !errors.As(err, &webFingerError) {
+		t.Error(err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error(err)
+	}
+
+	if message != nil {
+		t.FailNow()
+	}
+}
+
+func Test_Client_Do_Rels(t *testing.T) {
+	var baseURL string
+	var host string
+	renderWebFingerResponse := func() string {
+		return `
+		{
+			"subject": "acct:test@` + host + `",
+			"links": [
+					{
+							"rel": "http://webfinger.net/rel/profile-page",
+							"type": "text/html",
+							"href": "` + baseURL + `/@test"
+					},
+					{
+							"rel": "self",
+							"type": "application/activity+json",
+							"href": "` + baseURL + `/users/test"
+					}
+			]
+	}
+		`
+	}
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			rels := r.URL.Query()["rel"]
+			if len(rels) != 2 || rels[0] != "self" || rels[1] != "http://ostatus.org/schema/1.0/subscribe" {
+				t.Errorf("unexpected query value: %s: %v", "rel", rels)
+			}
+
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, renderWebFingerResponse())
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	baseURL = testServer.URL
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	request := &webfinger.Request{
+		Host:     host,
+		Resource: "acct:test@" + host,
+		Rels:     []string{"self", "http://ostatus.org/schema/1.0/subscribe"},
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	message, err := client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(message.Links) != 2 {
+		t.Errorf("unexpected links: %v", message.Links)
+	}
+
+	client.FilterLinksByRels = true
+
+	message, err = client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(message.Links) != 1 || message.Links[0].Rel != "self" {
+		t.Errorf("unexpected links: %v", message.Links)
+	}
+}
+
+func Test_Client_Do_ResponseTooLarge(t *testing.T) {
+	tests := []struct {
+		ContentType string
+		Body        string
+	}{
+		{
+			ContentType: "application/jrd+json",
+			Body:        `{"subject":"acct:test@localhost","aliases":["` + strings.Repeat("a", 2048) + `"]}`,
+		},
+		{
+			ContentType: "application/xrd+xml",
+			Body:        `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Subject>acct:test@localhost</Subject><Alias>` + strings.Repeat("a", 2048) + `</Alias></XRD>`,
+		},
+	}
+
+	for i, test := range tests {
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", test.ContentType)
+			if i%2 == 0 {
+				w.(http.Flusher).Flush()
+			}
+			io.WriteString(w, test.Body)
+		}))
+
+		u, err := url.Parse(testServer.URL)
+		if err != nil {
+			t.Error(err)
+		}
+
+		client := &webfinger.Client{
+			HTTPClient:      http.DefaultClient,
+			HTTPMode:        true,
+			MaxResponseSize: 1024,
+		}
+
+		_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+
+		var webFingerError *webfinger.Error
+		var responseTooLargeError *webfinger.ResponseTooLargeError
+		if !errors.As(err, &webFingerError) || !errors.As(err, &responseTooLargeError) {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+		} else if responseTooLargeError.Limit != 1024 {
+			t.Logf("case_index: %d, unexpected limit: %d", i, responseTooLargeError.Limit)
+			t.Fail()
+		}
+
+		testServer.Close()
+	}
+}
+
+func Test_Client_Do_DecodeLimits(t *testing.T) {
+	tests := []struct {
+		ContentType string
+		Body        string
+	}{
+		{
+			ContentType: "application/jrd+json",
+			Body:        `{"subject":"acct:test@localhost","links":[{"rel":"a"},{"rel":"b"},{"rel":"c"}]}`,
+		},
+		{
+			ContentType: "application/xrd+xml",
+			Body:        `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Subject>acct:test@localhost</Subject><Link rel="a"/><Link rel="b"/><Link rel="c"/></XRD>`,
+		},
+		{
+			ContentType: "application/xrd+xml",
+			Body:        `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Subject>acct:test@localhost</Subject>` + strings.Repeat("<a>", 10) + strings.Repeat("</a>", 10) + `</XRD>`,
+		},
+	}
+
+	for i, test := range tests {
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", test.ContentType)
+			io.WriteString(w, test.Body)
+		}))
+
+		u, err := url.Parse(testServer.URL)
+		if err != nil {
+			t.Error(err)
+		}
+
+		client := &webfinger.Client{
+			HTTPClient: http.DefaultClient,
+			HTTPMode:   true,
+			DecodeLimits: &webfinger.DecodeLimits{
+				MaxLinks:    2,
+				MaxXMLDepth: 8,
+			},
+		}
+
+		_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+
+		var webFingerError *webfinger.Error
+		var decodeLimitError *webfinger.DecodeLimitError
+		if !errors.As(err, &webFingerError) || !errors.As(err, &decodeLimitError) {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+		}
+
+		testServer.Close()
+	}
+}
+
 func Test_isXML(t *testing.T) {
 	tests := []struct {
 		Params struct {
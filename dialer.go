@@ -0,0 +1,199 @@
+package webfinger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+)
+
+var defaultForbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error)
+}
+
+type SafeDialer struct {
+	Dialer   *net.Dialer
+	Resolver HostResolver
+	Allow    []netip.Prefix
+	Deny     []netip.Prefix
+
+	mutex   sync.Mutex
+	clients map[*http.Client]*http.Client
+}
+
+func (d *SafeDialer) IsAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	for _, prefix := range d.Allow {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	for _, prefix := range d.Deny {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range defaultForbiddenPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (d *SafeDialer) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := d.resolve(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		if !d.IsAllowed(addr) {
+			lastErr = &ForbiddenDestinationError{
+				Host: host,
+				Addr: addr,
+			}
+			continue
+		}
+
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = &net.DNSError{
+			Err:        "no such host",
+			Name:       host,
+			IsNotFound: true,
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (d *SafeDialer) HTTPClient(base *http.Client) (*http.Client, error) {
+	if base == nil {
+		base = http.DefaultClient
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if client, ok := d.clients[base]; ok {
+		return client, nil
+	}
+
+	var transport *http.Transport
+	switch t := base.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, errors.New("safe dialer requires *http.Transport")
+	}
+
+	transport.Proxy = nil
+	transport.DialContext = d.DialContext
+	transport.DialTLSContext = nil
+	transport.Dial = nil
+	transport.DialTLS = nil
+
+	checkRedirect := base.CheckRedirect
+	client := &http.Client{
+		Transport: transport,
+		Jar:       base.Jar,
+		Timeout:   base.Timeout,
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if addr, err := netip.ParseAddr(request.URL.Hostname()); err == nil && !d.IsAllowed(addr) {
+				return &ForbiddenDestinationError{
+					Host: request.URL.Hostname(),
+					Addr: addr,
+				}
+			}
+
+			if checkRedirect != nil {
+				return checkRedirect(request, via)
+			}
+
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after %d redirects", len(via))
+			}
+
+			return nil
+		},
+	}
+
+	if d.clients == nil {
+		d.clients = map[*http.Client]*http.Client{}
+	}
+	d.clients[base] = client
+
+	return client, nil
+}
+
+func (d *SafeDialer) resolve(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ipNetwork := "ip"
+	switch network {
+	case "tcp4", "udp4":
+		ipNetwork = "ip4"
+	case "tcp6", "udp6":
+		ipNetwork = "ip6"
+	}
+
+	return resolver.LookupNetIP(ctx, ipNetwork, host)
+}
@@ -0,0 +1,130 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+type stubResolver map[string][]netip.Addr
+
+func (r stubResolver) LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return addrs, nil
+}
+
+func Test_SafeDialer_IsAllowed(t *testing.T) {
+	tests := []struct {
+		Addr     string
+		Allow    []netip.Prefix
+		Deny     []netip.Prefix
+		Expected bool
+	}{
+		{Addr: "127.0.0.1", Expected: false},
+		{Addr: "10.1.2.3", Expected: false},
+		{Addr: "172.16.0.1", Expected: false},
+		{Addr: "192.168.1.1", Expected: false},
+		{Addr: "169.254.169.254", Expected: false},
+		{Addr: "100.64.0.1", Expected: false},
+		{Addr: "0.0.0.0", Expected: false},
+		{Addr: "::1", Expected: false},
+		{Addr: "fe80::1", Expected: false},
+		{Addr: "fd00::1", Expected: false},
+		{Addr: "::ffff:127.0.0.1", Expected: false},
+		{Addr: "93.184.216.34", Expected: true},
+		{Addr: "2606:2800:220:1:248:1893:25c8:1946", Expected: true},
+		{Addr: "127.0.0.1", Allow: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}, Expected: true},
+		{Addr: "93.184.216.34", Deny: []netip.Prefix{netip.MustParsePrefix("93.184.216.0/24")}, Expected: false},
+	}
+
+	for i, test := range tests {
+		dialer := &webfinger.SafeDialer{
+			Allow: test.Allow,
+			Deny:  test.Deny,
+		}
+
+		actual := dialer.IsAllowed(netip.MustParseAddr(test.Addr))
+		if test.Expected != actual {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, test.Expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_Do_SafeDialer(t *testing.T) {
+	var redirectPort string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger" && r.URL.Query().Get("resource") == "acct:redirect@internal":
+			http.Redirect(w, r, "http://internal.test:"+redirectPort+"/.well-known/webfinger", http.StatusFound)
+		case r.URL.Path == "/.well-known/webfinger" && r.URL.Query().Get("resource") == "acct:redirect@ip":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case r.URL.Path == "/.well-known/webfinger":
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:test@public.test"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	redirectPort = u.Port()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		SafeDialer: &webfinger.SafeDialer{
+			Resolver: stubResolver{
+				"public.test":   {netip.MustParseAddr("127.0.0.1")},
+				"internal.test": {netip.MustParseAddr("127.0.0.2")},
+				"metadata.test": {netip.MustParseAddr("169.254.169.254")},
+			},
+			Allow: []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")},
+		},
+	}
+
+	message, err := client.Do(&webfinger.Request{Host: "public.test:" + u.Port(), Resource: "acct:test@public.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Subject != "acct:test@public.test" {
+		t.FailNow()
+	}
+
+	tests := []webfinger.Request{
+		{Host: "metadata.test", Resource: "acct:test@metadata.test"},
+		{Host: "127.0.0.2:" + u.Port(), Resource: "acct:test@internal.test"},
+		{Host: "public.test:" + u.Port(), Resource: "acct:redirect@internal"},
+		{Host: "public.test:" + u.Port(), Resource: "acct:redirect@ip"},
+	}
+
+	for i, test := range tests {
+		_, err := client.Do(&test)
+
+		var webFingerError *webfinger.Error
+		var forbiddenDestinationError *webfinger.ForbiddenDestinationError
+		if !errors.As(err, &webFingerError) || !errors.As(err, &forbiddenDestinationError) {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+		}
+	}
+}
@@ -3,10 +3,14 @@ package webfinger
 import (
 	"errors"
 	"fmt"
+	"net/netip"
+	"strings"
+	"time"
 )
 
 var (
 	ErrInvalidResponse  = errors.New("invalid response")
+	ErrInvalidResource  = errors.New("invalid resource")
 	ErrResourceNotFound = errors.New("resource not found")
 )
 
@@ -27,12 +31,26 @@ func (e *Error) Error() string {
 }
 
 type WebFingerResponseStatusError struct {
-	URL        string
-	StatusCode int
-	Status     string
+	Host        string
+	URL         string
+	StatusCode  int
+	Status      string
+	ContentType string
+	Body        []byte
+	Problem     *ProblemDetails
+	RetryAfter  time.Duration
+	RateLimit   *RateLimit
 }
 
 func (e *WebFingerResponseStatusError) Error() string {
+	if e.Problem != nil && e.Problem.Detail != "" {
+		return fmt.Sprintf("webfinger response status error: %d %s: %s", e.StatusCode, e.Status, e.Problem.Detail)
+	}
+
+	if e.Problem != nil && e.Problem.Title != "" {
+		return fmt.Sprintf("webfinger response status error: %d %s: %s", e.StatusCode, e.Status, e.Problem.Title)
+	}
+
 	return fmt.Sprintf("webfinger response status error: %d %s", e.StatusCode, e.Status)
 }
 
@@ -43,3 +61,190 @@ type UnsupportedContentTypeError struct {
 func (e *UnsupportedContentTypeError) Error() string {
 	return fmt.Sprintf("unsupported content type error: %s", e.ContentType)
 }
+
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response too large error: exceeds %d bytes", e.Limit)
+}
+
+type DecodeLimitError struct {
+	Name  string
+	Limit int
+}
+
+func (e *DecodeLimitError) Error() string {
+	return fmt.Sprintf("decode limit error: %s exceeds %d", e.Name, e.Limit)
+}
+
+type ForbiddenDestinationError struct {
+	Host string
+	Addr netip.Addr
+}
+
+func (e *ForbiddenDestinationError) Error() string {
+	return fmt.Sprintf("forbidden destination error: %s (%s)", e.Host, e.Addr)
+}
+
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("retry error: gave up after %d attempts: %s", e.Attempts, e.Err)
+}
+
+type RateLimitedError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited error: %s (retry after %s)", e.Host, e.RetryAfter)
+}
+
+type RedirectError struct {
+	Err   error
+	Chain []string
+}
+
+func (e *RedirectError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("redirect error: %s: %s", e.Err, strings.Join(e.Chain, " -> "))
+}
+
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		violations = append(violations, v.String())
+	}
+
+	return fmt.Sprintf("validation error: %s", strings.Join(violations, "; "))
+}
+
+type BindingError struct {
+	Err      error
+	Resource string
+	Subject  string
+	Host     string
+}
+
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+func (e *BindingError) Error() string {
+	return fmt.Sprintf("binding error: %s: resource=%s subject=%s host=%s", e.Err, e.Resource, e.Subject, e.Host)
+}
+
+type CanonicalError struct {
+	Err   error
+	Chain []string
+}
+
+func (e *CanonicalError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CanonicalError) Error() string {
+	return fmt.Sprintf("canonical error: %s: %s", e.Err, strings.Join(e.Chain, " -> "))
+}
+
+type TransportError struct {
+	Host string
+	URL  string
+	Err  error
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("transport error: %s: %s", e.Host, e.Err)
+}
+
+type DNSError struct {
+	Host string
+	URL  string
+	Err  error
+}
+
+func (e *DNSError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DNSError) Error() string {
+	return fmt.Sprintf("dns error: %s: %s", e.Host, e.Err)
+}
+
+type TLSError struct {
+	Host string
+	URL  string
+	Err  error
+}
+
+func (e *TLSError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TLSError) Error() string {
+	return fmt.Sprintf("tls error: %s: %s", e.Host, e.Err)
+}
+
+type TimeoutError struct {
+	Host string
+	URL  string
+	Err  error
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timeout error: %s: %s", e.Host, e.Err)
+}
+
+type DecodeError struct {
+	Host        string
+	URL         string
+	ContentType string
+	Err         error
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode error: %s (%s): %s", e.Host, e.ContentType, e.Err)
+}
+
+type PolicyError struct {
+	Host string
+	URL  string
+	Err  error
+}
+
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("policy error: %s: %s", e.Host, e.Err)
+}
@@ -1,8 +1,11 @@
 package webfinger_test
 
 import (
+	"context"
 	"errors"
+	"net/netip"
 	"testing"
+	"time"
 
 	webfinger "github.com/MitarashiDango/go-webfinger"
 )
@@ -49,6 +52,20 @@ func Test_WebFingerResponseStatusError_Error_001(t *testing.T) {
 	}
 }
 
+func Test_WebFingerResponseStatusError_Error_002(t *testing.T) {
+	e := &webfinger.WebFingerResponseStatusError{
+		StatusCode: 400,
+		Status:     "400 Bad Request",
+		Problem: &webfinger.ProblemDetails{
+			Title:  "Invalid resource",
+			Detail: "resource must be an acct URI",
+		},
+	}
+	if err := e.Error(); err != "webfinger response status error: 400 400 Bad Request: resource must be an acct URI" {
+		t.FailNow()
+	}
+}
+
 func Test_UnsupportedContentTypeError_Error_001(t *testing.T) {
 	e := &webfinger.UnsupportedContentTypeError{
 		ContentType: "test content type",
@@ -57,3 +74,163 @@ func Test_UnsupportedContentTypeError_Error_001(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func Test_ResponseTooLargeError_Error_001(t *testing.T) {
+	e := &webfinger.ResponseTooLargeError{
+		Limit: 1024,
+	}
+	if err := e.Error(); err != "response too large error: exceeds 1024 bytes" {
+		t.FailNow()
+	}
+}
+
+func Test_DecodeLimitError_Error_001(t *testing.T) {
+	e := &webfinger.DecodeLimitError{
+		Name:  "links",
+		Limit: 10,
+	}
+	if err := e.Error(); err != "decode limit error: links exceeds 10" {
+		t.FailNow()
+	}
+}
+
+func Test_ForbiddenDestinationError_Error_001(t *testing.T) {
+	e := &webfinger.ForbiddenDestinationError{
+		Host: "localhost",
+		Addr: netip.MustParseAddr("127.0.0.1"),
+	}
+	if err := e.Error(); err != "forbidden destination error: localhost (127.0.0.1)" {
+		t.FailNow()
+	}
+}
+
+func Test_RetryError_Error_001(t *testing.T) {
+	e := &webfinger.RetryError{
+		Attempts: 3,
+		Err:      errors.New("test error"),
+	}
+	if err := e.Error(); err != "retry error: gave up after 3 attempts: test error" {
+		t.FailNow()
+	}
+}
+
+func Test_RateLimitedError_Error_001(t *testing.T) {
+	e := &webfinger.RateLimitedError{
+		Host:       "localhost",
+		RetryAfter: time.Second,
+	}
+	if err := e.Error(); err != "rate limited error: localhost (retry after 1s)" {
+		t.FailNow()
+	}
+}
+
+func Test_RedirectError_Error_001(t *testing.T) {
+	e := &webfinger.RedirectError{
+		Err:   webfinger.ErrRedirectLoop,
+		Chain: []string{"https://localhost/a", "https://localhost/b", "https://localhost/a"},
+	}
+	if err := e.Error(); err != "redirect error: redirect loop: https://localhost/a -> https://localhost/b -> https://localhost/a" {
+		t.FailNow()
+	}
+}
+
+func Test_ValidationError_Error_001(t *testing.T) {
+	e := &webfinger.ValidationError{
+		Violations: []webfinger.Violation{
+			{Field: "subject", Description: "missing"},
+			{Field: "links[0].rel", Description: "missing"},
+		},
+	}
+	if err := e.Error(); err != "validation error: subject: missing; links[0].rel: missing" {
+		t.FailNow()
+	}
+}
+
+func Test_BindingError_Error_001(t *testing.T) {
+	e := &webfinger.BindingError{
+		Err:      webfinger.ErrHostMismatch,
+		Resource: "acct:test@example.com",
+		Subject:  "acct:test@example.com",
+		Host:     "evil.example",
+	}
+	if err := e.Error(); err != "binding error: host is not authoritative for resource: resource=acct:test@example.com subject=acct:test@example.com host=evil.example" {
+		t.FailNow()
+	}
+}
+
+func Test_CanonicalError_Error_001(t *testing.T) {
+	e := &webfinger.CanonicalError{
+		Err:   webfinger.ErrCanonicalLoop,
+		Chain: []string{"acct:test@a.example", "acct:test@b.example", "acct:test@a.example"},
+	}
+	if err := e.Error(); err != "canonical error: domains point at each other: acct:test@a.example -> acct:test@b.example -> acct:test@a.example" {
+		t.FailNow()
+	}
+}
+
+func Test_TransportError_Error_001(t *testing.T) {
+	e := &webfinger.TransportError{
+		Host: "example.com",
+		URL:  "https://example.com/.well-known/webfinger",
+		Err:  errors.New("connection reset"),
+	}
+	if err := e.Error(); err != "transport error: example.com: connection reset" {
+		t.FailNow()
+	}
+}
+
+func Test_DNSError_Error_001(t *testing.T) {
+	e := &webfinger.DNSError{
+		Host: "example.com",
+		URL:  "https://example.com/.well-known/webfinger",
+		Err:  errors.New("no such host"),
+	}
+	if err := e.Error(); err != "dns error: example.com: no such host" {
+		t.FailNow()
+	}
+}
+
+func Test_TLSError_Error_001(t *testing.T) {
+	e := &webfinger.TLSError{
+		Host: "example.com",
+		URL:  "https://example.com/.well-known/webfinger",
+		Err:  errors.New("certificate signed by unknown authority"),
+	}
+	if err := e.Error(); err != "tls error: example.com: certificate signed by unknown authority" {
+		t.FailNow()
+	}
+}
+
+func Test_TimeoutError_Error_001(t *testing.T) {
+	e := &webfinger.TimeoutError{
+		Host: "example.com",
+		URL:  "https://example.com/.well-known/webfinger",
+		Err:  context.DeadlineExceeded,
+	}
+	if err := e.Error(); err != "timeout error: example.com: context deadline exceeded" {
+		t.FailNow()
+	}
+}
+
+func Test_DecodeError_Error_001(t *testing.T) {
+	e := &webfinger.DecodeError{
+		Host:        "example.com",
+		URL:         "https://example.com/.well-known/webfinger",
+		ContentType: "application/jrd+json",
+		Err:         errors.New("unexpected end of JSON input"),
+	}
+	if err := e.Error(); err != "decode error: example.com (application/jrd+json): unexpected end of JSON input" {
+		t.FailNow()
+	}
+}
+
+func Test_PolicyError_Error_001(t *testing.T) {
+	e := &webfinger.PolicyError{
+		Host: "example.com",
+		URL:  "https://example.com/.well-known/webfinger",
+		Err:  &webfinger.ResponseTooLargeError{Limit: 1024},
+	}
+	if err := e.Error(); err != "policy error: example.com: response too large error: exceeds 1024 bytes" {
+		t.FailNow()
+	}
+}
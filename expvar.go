@@ -0,0 +1,79 @@
+package webfinger
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+)
+
+type ExpvarObserver struct {
+	Lookups        *expvar.Map
+	LookupSeconds  *expvar.Map
+	Traces         *expvar.Map
+	TraceSeconds   *expvar.Map
+	Retries        *expvar.Map
+	CacheHits      *expvar.Map
+	DecodeFailures *expvar.Map
+}
+
+// NewExpvarObserver publishes the counters under name and, like expvar.NewMap,
+// panics if name is already registered.
+func NewExpvarObserver(name string) *ExpvarObserver {
+	observer := &ExpvarObserver{
+		Lookups:        new(expvar.Map),
+		LookupSeconds:  new(expvar.Map),
+		Traces:         new(expvar.Map),
+		TraceSeconds:   new(expvar.Map),
+		Retries:        new(expvar.Map),
+		CacheHits:      new(expvar.Map),
+		DecodeFailures: new(expvar.Map),
+	}
+
+	m := expvar.NewMap(name)
+	m.Set("lookups", observer.Lookups)
+	m.Set("lookup_seconds", observer.LookupSeconds)
+	m.Set("traces", observer.Traces)
+	m.Set("trace_seconds", observer.TraceSeconds)
+	m.Set("retries", observer.Retries)
+	m.Set("cache_hits", observer.CacheHits)
+	m.Set("decode_failures", observer.DecodeFailures)
+
+	return observer
+}
+
+func (o *ExpvarObserver) LookupStart(context.Context, LookupStartEvent) {}
+
+func (o *ExpvarObserver) LookupEnd(_ context.Context, event LookupEndEvent) {
+	o.Lookups.Add("host="+event.Host+",outcome="+string(event.Outcome)+",status="+strconv.Itoa(event.StatusCode), 1)
+	o.LookupSeconds.AddFloat("host="+event.Host+",outcome="+string(event.Outcome), event.Elapsed.Seconds())
+}
+
+func (o *ExpvarObserver) Trace(_ context.Context, event TraceEvent) {
+	outcome := "success"
+	if event.Err != nil {
+		outcome = "error"
+	}
+
+	o.Traces.Add("host="+event.Host+",phase="+string(event.Phase)+",outcome="+outcome, 1)
+	o.TraceSeconds.AddFloat("host="+event.Host+",phase="+string(event.Phase), event.Duration.Seconds())
+}
+
+func (o *ExpvarObserver) Retry(_ context.Context, event RetryEvent) {
+	o.Retries.Add("host="+event.Host+",status="+strconv.Itoa(event.StatusCode), 1)
+}
+
+func (o *ExpvarObserver) CacheHit(_ context.Context, event CacheHitEvent) {
+	kind := "fresh"
+	switch {
+	case event.NotFound:
+		kind = "not_found"
+	case event.Revalidated:
+		kind = "revalidated"
+	}
+
+	o.CacheHits.Add("host="+event.Host+",kind="+kind, 1)
+}
+
+func (o *ExpvarObserver) DecodeFailure(_ context.Context, event DecodeFailureEvent) {
+	o.DecodeFailures.Add("host="+event.Host+",content_type="+event.ContentType, 1)
+}
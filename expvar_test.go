@@ -0,0 +1,76 @@
+package webfinger_test
+
+import (
+	"context"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_ExpvarObserver(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		w.Header().Set("Cache-Control", "max-age=60")
+		io.WriteString(w, `{"subject":"acct:test@example.com"}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	observer := &webfinger.ExpvarObserver{
+		Lookups:        new(expvar.Map),
+		LookupSeconds:  new(expvar.Map),
+		Traces:         new(expvar.Map),
+		TraceSeconds:   new(expvar.Map),
+		Retries:        new(expvar.Map),
+		CacheHits:      new(expvar.Map),
+		DecodeFailures: new(expvar.Map),
+	}
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		Cache:      webfinger.NewLRUCache(0),
+		Observer:   observer,
+	}
+
+	for range 2 {
+		if _, err := client.DoContext(context.Background(), &webfinger.Request{
+			Host:     u.Host,
+			Resource: "acct:test@example.com",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if v := observer.Lookups.Get("host=" + u.Host + ",outcome=success,status=200"); v == nil || v.String() != "2" {
+		t.Errorf("unexpected lookups: %s", observer.Lookups.String())
+	}
+
+	if v := observer.CacheHits.Get("host=" + u.Host + ",kind=fresh"); v == nil || v.String() != "1" {
+		t.Errorf("unexpected cache hits: %s", observer.CacheHits.String())
+	}
+
+	if v := observer.Traces.Get("host=" + u.Host + ",phase=connect,outcome=success"); v == nil || v.String() != "1" {
+		t.Errorf("unexpected traces: %s", observer.Traces.String())
+	}
+}
+
+func Test_NewExpvarObserver(t *testing.T) {
+	name := "webfinger_test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	observer := webfinger.NewExpvarObserver(name)
+
+	published, ok := expvar.Get(name).(*expvar.Map)
+	if !ok || published.Get("lookups") != observer.Lookups {
+		t.Errorf("unexpected published var: %v", expvar.Get(name))
+	}
+}
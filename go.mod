@@ -2,4 +2,9 @@ module github.com/MitarashiDango/go-webfinger
 
 go 1.23.1
 
-require github.com/MitarashiDango/go-nullable v1.0.3
+require (
+	github.com/MitarashiDango/go-nullable v1.0.3
+	golang.org/x/net v0.43.0
+)
+
+require golang.org/x/text v0.28.0 // indirect
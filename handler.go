@@ -0,0 +1,123 @@
+package webfinger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+type Resolver interface {
+	Resolve(ctx context.Context, resource string, rels []string) (*Message, error)
+}
+
+type ResolverFunc func(ctx context.Context, resource string, rels []string) (*Message, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, resource string, rels []string) (*Message, error) {
+	return f(ctx, resource, rels)
+}
+
+type Handler struct {
+	Resolver       Resolver
+	Logger         *slog.Logger
+	RedactResource func(resource string) string
+}
+
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeHTTPError(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	resource, rels, err := parseHandlerQuery(r.URL.RawQuery)
+	if err != nil {
+		message := err.Error()
+		for _, resource := range r.URL.Query()["resource"] {
+			message = redactString(message, resource, handler.RedactResource)
+		}
+
+		handler.log(r.Context(), slog.LevelDebug, "webfinger invalid query",
+			slog.String("error", message))
+		writeHTTPError(w, http.StatusBadRequest)
+		return
+	}
+
+	handler.log(r.Context(), slog.LevelDebug, "webfinger request",
+		slog.String("resource", handler.redact(resource)),
+		slog.Any("rels", rels))
+
+	message, err := handler.Resolver.Resolve(r.Context(), resource, rels)
+	switch {
+	case errors.Is(err, ErrInvalidResource):
+		writeHTTPError(w, http.StatusBadRequest)
+		return
+	case errors.Is(err, ErrResourceNotFound):
+		writeHTTPError(w, http.StatusNotFound)
+		return
+	case err != nil:
+		handler.log(r.Context(), slog.LevelWarn, "webfinger resolver failed",
+			slog.String("resource", handler.redact(resource)),
+			slog.String("error", redactString(err.Error(), resource, handler.RedactResource)))
+		writeHTTPError(w, http.StatusInternalServerError)
+		return
+	case message == nil:
+		writeHTTPError(w, http.StatusNotFound)
+		return
+	}
+
+	if len(rels) > 0 {
+		filtered := *message
+		filtered.Links = message.GetLinksByRelationTypes(rels...)
+		message = &filtered
+	}
+
+	b, err := json.Marshal(message)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jrd+json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func parseHandlerQuery(rawQuery string) (string, []string, error) {
+	queries, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resources := queries["resource"]
+	if len(resources) != 1 || resources[0] == "" {
+		return "", nil, ErrInvalidResource
+	}
+
+	resourceURI, err := url.Parse(resources[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	if resourceURI.Scheme == "" {
+		return "", nil, ErrInvalidResource
+	}
+
+	rels := make([]string, 0, len(queries["rel"]))
+	for _, rel := range queries["rel"] {
+		if rel == "" {
+			continue
+		}
+		rels = append(rels, rel)
+	}
+
+	return resources[0], rels, nil
+}
+
+func writeHTTPError(w http.ResponseWriter, statusCode int) {
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
@@ -0,0 +1,161 @@
+package webfinger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func newTestHandler() *webfinger.Handler {
+	return &webfinger.Handler{
+		Resolver: webfinger.ResolverFunc(func(ctx context.Context, resource string, rels []string) (*webfinger.Message, error) {
+			switch resource {
+			case "acct:test@localhost":
+				return &webfinger.Message{
+					Subject: "acct:test@localhost",
+					Links: []webfinger.Link{
+						{
+							Rel:  "http://webfinger.net/rel/profile-page",
+							Type: "text/html",
+							Href: "http://localhost/@test",
+						},
+						{
+							Rel:  "self",
+							Type: "application/activity+json",
+							Href: "http://localhost/users/test",
+						},
+					},
+				}, nil
+			case "acct:invalid@localhost":
+				return nil, webfinger.ErrInvalidResource
+			case "acct:broken@localhost":
+				return nil, errors.New("test error")
+			default:
+				return nil, webfinger.ErrResourceNotFound
+			}
+		}),
+	}
+}
+
+func Test_Handler_ServeHTTP_OK(t *testing.T) {
+	handler := newTestHandler()
+
+	r := httptest.NewRequest("GET", "/.well-known/webfinger?resource="+url.QueryEscape("acct:test@localhost"), nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "application/jrd+json" {
+		t.Errorf("unexpected content type: %s", w.Header().Get("Content-Type"))
+	}
+
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("unexpected access control allow origin: %s", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	var message webfinger.Message
+	if err := json.Unmarshal(w.Body.Bytes(), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Subject != "acct:test@localhost" || len(message.Links) != 2 {
+		t.Errorf("unexpected message: %v", message)
+	}
+}
+
+func Test_Handler_ServeHTTP_Rels(t *testing.T) {
+	handler := newTestHandler()
+
+	r := httptest.NewRequest("GET", "/.well-known/webfinger?resource="+url.QueryEscape("acct:test@localhost")+"&rel=self", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", w.Code)
+	}
+
+	var message webfinger.Message
+	if err := json.Unmarshal(w.Body.Bytes(), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(message.Links) != 1 || message.Links[0].Rel != "self" {
+		t.Errorf("unexpected links: %v", message.Links)
+	}
+}
+
+func Test_Handler_ServeHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		Method   string
+		Target   string
+		Expected int
+	}{
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger?resource=" + url.QueryEscape("acct:notfound@localhost"),
+			Expected: http.StatusNotFound,
+		},
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger?resource=" + url.QueryEscape("acct:invalid@localhost"),
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger?resource=" + url.QueryEscape("acct:broken@localhost"),
+			Expected: http.StatusInternalServerError,
+		},
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger",
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger?resource=test",
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger?resource=acct:a@localhost&resource=acct:b@localhost",
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Method:   "GET",
+			Target:   "/.well-known/webfinger?resource=%zz",
+			Expected: http.StatusBadRequest,
+		},
+		{
+			Method:   "POST",
+			Target:   "/.well-known/webfinger?resource=" + url.QueryEscape("acct:test@localhost"),
+			Expected: http.StatusMethodNotAllowed,
+		},
+	}
+
+	handler := newTestHandler()
+
+	for i, test := range tests {
+		r := httptest.NewRequest(test.Method, test.Target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != test.Expected {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, test.Expected, w.Code)
+			t.Fail()
+		}
+
+		if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Logf("case_index: %d, missing access control allow origin", i)
+			t.Fail()
+		}
+	}
+}
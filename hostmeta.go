@@ -0,0 +1,115 @@
+package webfinger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const relLRDD = "lrdd"
+
+func isHostMetaFallbackError(err error) bool {
+	var unsupportedContentTypeError *UnsupportedContentTypeError
+	return errors.Is(err, ErrResourceNotFound) || errors.As(err, &unsupportedContentTypeError)
+}
+
+func (client *Client) doHostMetaFallback(ctx context.Context, webFingerRequest *Request, originalErr error) (*Message, *http.Response, error) {
+	template, err := client.fetchLRDDTemplate(ctx, webFingerRequest.Host)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, originalErr
+	}
+
+	request, err := client.newHTTPRequest(ctx, expandLRDDTemplate(template, webFingerRequest.Resource), "application/jrd+json, application/xrd+xml")
+	if err != nil {
+		return nil, nil, &Error{
+			Err: err,
+		}
+	}
+
+	return client.fetchMessage(ctx, request)
+}
+
+func (client *Client) fetchLRDDTemplate(ctx context.Context, host string) (string, error) {
+	candidates := []struct {
+		path   string
+		accept string
+	}{
+		{
+			path:   "/.well-known/host-meta",
+			accept: "application/xrd+xml",
+		},
+		{
+			path:   "/.well-known/host-meta.json",
+			accept: "application/json, application/jrd+json",
+		},
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		hostMetaURL := getSchema(client.HTTPMode) + "//" + host + candidate.path
+
+		request, err := client.newHTTPRequest(ctx, hostMetaURL, candidate.accept)
+		if err != nil {
+			return "", &Error{
+				Err: err,
+			}
+		}
+
+		hostMeta, _, err := client.fetchMessage(ctx, request)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
+			lastErr = err
+			continue
+		}
+
+		template, err := findLRDDTemplate(hostMeta, hostMetaURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return template, nil
+	}
+
+	return "", lastErr
+}
+
+func findLRDDTemplate(hostMeta *Message, hostMetaURL string) (string, error) {
+	base, err := url.Parse(hostMetaURL)
+	if err != nil {
+		return "", err
+	}
+
+	for _, link := range hostMeta.GetLinksByRelationTypes(relLRDD) {
+		if link.Template == "" || !strings.Contains(link.Template, "{uri}") {
+			continue
+		}
+
+		ref, err := url.Parse(strings.ReplaceAll(link.Template, "{uri}", ""))
+		if err != nil {
+			continue
+		}
+
+		if ref.IsAbs() {
+			return link.Template, nil
+		}
+
+		return base.Scheme + "://" + base.Host + "/" + strings.TrimPrefix(link.Template, "/"), nil
+	}
+
+	return "", &Error{
+		Err: ErrInvalidResponse,
+	}
+}
+
+func expandLRDDTemplate(template string, resource string) string {
+	return strings.ReplaceAll(template, "{uri}", url.QueryEscape(resource))
+}
@@ -0,0 +1,156 @@
+package webfinger_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_Do_HostMetaFallback_XML(t *testing.T) {
+	var baseURL string
+	var host string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			w.WriteHeader(404)
+		case r.URL.Path == "/.well-known/host-meta":
+			w.Header().Set("Content-Type", "application/xrd+xml")
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0">
+<Link rel="lrdd" type="application/xrd+xml" template="`+baseURL+`/describe?uri={uri}"/>
+</XRD>`)
+		case r.URL.Path == "/describe":
+			if r.URL.Query().Get("uri") != "acct:test@"+host {
+				t.Errorf("unexpected query value: %s: %s", "uri", r.URL.Query().Get("uri"))
+			}
+
+			w.Header().Set("Content-Type", "application/xrd+xml")
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0">
+<Subject>acct:test@`+host+`</Subject>
+<Link rel="self" type="application/activity+json" href="`+baseURL+`/users/test"/>
+</XRD>`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	baseURL = testServer.URL
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		HostMetaFallback: true,
+	}
+
+	message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Subject != "acct:test@"+host {
+		t.FailNow()
+	}
+}
+
+func Test_Client_Do_HostMetaFallback_JSON(t *testing.T) {
+	var baseURL string
+	var host string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger":
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, "<html></html>")
+		case r.URL.Path == "/.well-known/host-meta":
+			w.WriteHeader(404)
+		case r.URL.Path == "/.well-known/host-meta.json":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"links":[{"rel":"lrdd","type":"application/jrd+json","template":"/describe?uri={uri}"}]}`)
+		case r.URL.Path == "/describe":
+			if r.URL.Query().Get("uri") != "acct:test@"+host {
+				t.Errorf("unexpected query value: %s: %s", "uri", r.URL.Query().Get("uri"))
+			}
+
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:test@`+host+`"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	baseURL = testServer.URL
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		HostMetaFallback: true,
+	}
+
+	message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Subject != "acct:test@"+host {
+		t.FailNow()
+	}
+}
+
+func Test_Client_Do_HostMetaFallback_NoHostMeta(t *testing.T) {
+	var host string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/webfinger", "/.well-known/host-meta", "/.well-known/host-meta.json":
+			w.WriteHeader(404)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	host = u.Host
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		HostMetaFallback: true,
+	}
+
+	message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@" + host})
+	if !errors.Is(err, webfinger.ErrResourceNotFound) {
+		t.Error(err)
+	}
+
+	if message != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,176 @@
+package webfinger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultHostRate          = 5.0
+	DefaultHostBurst         = 10
+	DefaultHostMaxConcurrent = 4
+)
+
+const hostLimiterSweepThreshold = 1024
+
+type Limiter interface {
+	Acquire(ctx context.Context, host string, wait bool) (func(), error)
+}
+
+type HostLimiter struct {
+	Rate          float64
+	Burst         int
+	MaxConcurrent int
+
+	mutex sync.Mutex
+	hosts map[string]*hostLimiterState
+}
+
+type hostLimiterState struct {
+	tokens  float64
+	last    time.Time
+	active  int
+	changed chan struct{}
+}
+
+func NewHostLimiter(rate float64, burst int, maxConcurrent int) *HostLimiter {
+	return &HostLimiter{
+		Rate:          rate,
+		Burst:         burst,
+		MaxConcurrent: maxConcurrent,
+	}
+}
+
+func (l *HostLimiter) Acquire(ctx context.Context, host string, wait bool) (func(), error) {
+	host = strings.ToLower(host)
+
+	for {
+		l.mutex.Lock()
+		state := l.state(host)
+
+		now := time.Now()
+		state.tokens = min(state.tokens+now.Sub(state.last).Seconds()*l.rate(), float64(l.burst()))
+		state.last = now
+
+		if state.tokens >= 1 && state.active < l.maxConcurrent() {
+			state.tokens--
+			state.active++
+			l.mutex.Unlock()
+
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					l.release(host)
+				})
+			}, nil
+		}
+
+		var delay time.Duration
+		if state.tokens < 1 {
+			delay = time.Duration((1 - state.tokens) / l.rate() * float64(time.Second))
+		}
+		changed := state.changed
+		l.mutex.Unlock()
+
+		if !wait {
+			return nil, &RateLimitedError{
+				Host:       host,
+				RetryAfter: delay,
+			}
+		}
+
+		if err := waitLimiter(ctx, changed, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func waitLimiter(ctx context.Context, changed <-chan struct{}, delay time.Duration) error {
+	var timeout <-chan time.Time
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+		return nil
+	case <-timeout:
+		return nil
+	}
+}
+
+func (l *HostLimiter) release(host string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	state, ok := l.hosts[host]
+	if !ok {
+		return
+	}
+
+	state.active--
+	close(state.changed)
+	state.changed = make(chan struct{})
+}
+
+func (l *HostLimiter) state(host string) *hostLimiterState {
+	if l.hosts == nil {
+		l.hosts = map[string]*hostLimiterState{}
+	}
+
+	if state, ok := l.hosts[host]; ok {
+		return state
+	}
+
+	if len(l.hosts) >= hostLimiterSweepThreshold {
+		l.sweep()
+	}
+
+	state := &hostLimiterState{
+		tokens:  float64(l.burst()),
+		last:    time.Now(),
+		changed: make(chan struct{}),
+	}
+	l.hosts[host] = state
+
+	return state
+}
+
+func (l *HostLimiter) sweep() {
+	now := time.Now()
+	for host, state := range l.hosts {
+		if state.active == 0 && state.tokens+now.Sub(state.last).Seconds()*l.rate() >= float64(l.burst()) {
+			delete(l.hosts, host)
+		}
+	}
+}
+
+func (l *HostLimiter) rate() float64 {
+	if l.Rate <= 0 {
+		return DefaultHostRate
+	}
+
+	return l.Rate
+}
+
+func (l *HostLimiter) burst() int {
+	if l.Burst <= 0 {
+		return DefaultHostBurst
+	}
+
+	return l.Burst
+}
+
+func (l *HostLimiter) maxConcurrent() int {
+	if l.MaxConcurrent <= 0 {
+		return DefaultHostMaxConcurrent
+	}
+
+	return l.MaxConcurrent
+}
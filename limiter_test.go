@@ -0,0 +1,129 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_HostLimiter_FailFast(t *testing.T) {
+	limiter := webfinger.NewHostLimiter(1, 1, 0)
+
+	release, err := limiter.Acquire(context.Background(), "example.com", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	_, err = limiter.Acquire(context.Background(), "EXAMPLE.com", false)
+
+	var rateLimitedError *webfinger.RateLimitedError
+	if !errors.As(err, &rateLimitedError) {
+		t.Fatal(err)
+	}
+
+	if rateLimitedError.Host != "example.com" || rateLimitedError.RetryAfter <= 0 {
+		t.Errorf("unexpected error: %v", rateLimitedError)
+	}
+
+	release, err = limiter.Acquire(context.Background(), "example.org", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func Test_HostLimiter_Concurrency(t *testing.T) {
+	limiter := webfinger.NewHostLimiter(1000, 10, 1)
+
+	release, err := limiter.Acquire(context.Background(), "example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := limiter.Acquire(context.Background(), "example.com", false); err == nil {
+		t.Fatal("expected error")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := limiter.Acquire(context.Background(), "example.com", true)
+		if err != nil {
+			t.Error(err)
+		} else {
+			release()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired while the concurrency limit was reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	release()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("not acquired after release")
+	}
+}
+
+func Test_HostLimiter_Canceled(t *testing.T) {
+	limiter := webfinger.NewHostLimiter(0.001, 1, 0)
+
+	release, err := limiter.Acquire(context.Background(), "example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.Acquire(ctx, "example.com", true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error(err)
+	}
+}
+
+func Test_Client_Do_RateLimitFailFast(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:        http.DefaultClient,
+		HTTPMode:          true,
+		Limiter:           webfinger.NewHostLimiter(0.001, 1, 0),
+		RateLimitFailFast: true,
+	}
+
+	if _, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+
+	var webFingerError *webfinger.Error
+	var rateLimitedError *webfinger.RateLimitedError
+	if !errors.As(err, &webFingerError) || !errors.As(err, &rateLimitedError) {
+		t.Error(err)
+	}
+}
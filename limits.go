@@ -0,0 +1,120 @@
+package webfinger
+
+import (
+	"encoding/xml"
+)
+
+const DefaultMaxResponseSize int64 = 1 << 20
+
+type DecodeLimits struct {
+	MaxLinks      int
+	MaxAliases    int
+	MaxProperties int
+	MaxXMLDepth   int
+}
+
+var DefaultDecodeLimits = DecodeLimits{
+	MaxLinks:      256,
+	MaxAliases:    256,
+	MaxProperties: 256,
+	MaxXMLDepth:   32,
+}
+
+type limitedMessage struct {
+	message *Message
+	limits  *DecodeLimits
+}
+
+func (m *limitedMessage) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return m.message.unmarshalXML(d, start, m.limits)
+}
+
+func (limits *DecodeLimits) check(name string, limit int, count int) error {
+	if limit > 0 && count > limit {
+		return &DecodeLimitError{
+			Name:  name,
+			Limit: limit,
+		}
+	}
+
+	return nil
+}
+
+func (limits *DecodeLimits) checkMessage(message *Message) error {
+	if err := limits.check("links", limits.MaxLinks, len(message.Links)); err != nil {
+		return err
+	}
+
+	if err := limits.check("aliases", limits.MaxAliases, len(message.Aliases)); err != nil {
+		return err
+	}
+
+	if err := limits.check("properties", limits.MaxProperties, len(message.Properties)); err != nil {
+		return err
+	}
+
+	for _, link := range message.Links {
+		if err := limits.check("properties", limits.MaxProperties, len(link.Properties)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type limitedTokenReader struct {
+	decoder        *xml.Decoder
+	limits         *DecodeLimits
+	start          *xml.StartElement
+	depth          int
+	links          int
+	aliases        int
+	properties     int
+	linkProperties int
+}
+
+func (t *limitedTokenReader) Token() (xml.Token, error) {
+	if t.start != nil {
+		start := *t.start
+		t.start = nil
+		t.depth = 1
+		return start, nil
+	}
+
+	token, err := t.decoder.Token()
+	if err != nil {
+		return token, err
+	}
+
+	switch token := token.(type) {
+	case xml.StartElement:
+		t.depth++
+		if err := t.limits.check("xml depth", t.limits.MaxXMLDepth, t.depth); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case t.depth == 2 && token.Name.Local == "Link":
+			t.links++
+			t.linkProperties = 0
+			err = t.limits.check("links", t.limits.MaxLinks, t.links)
+		case t.depth == 2 && token.Name.Local == "Alias":
+			t.aliases++
+			err = t.limits.check("aliases", t.limits.MaxAliases, t.aliases)
+		case t.depth == 2 && token.Name.Local == "Property":
+			t.properties++
+			err = t.limits.check("properties", t.limits.MaxProperties, t.properties)
+		case t.depth == 3 && token.Name.Local == "Property":
+			t.linkProperties++
+			err = t.limits.check("properties", t.limits.MaxProperties, t.linkProperties)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	case xml.EndElement:
+		t.depth--
+	}
+
+	return token, nil
+}
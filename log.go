@@ -0,0 +1,76 @@
+package webfinger
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaskResource is a RedactResource function that keeps only the scheme and
+// host of a resource, e.g. "acct:*@example.com".
+func MaskResource(resource string) string {
+	if strings.HasPrefix(strings.ToLower(resource), "acct:") {
+		if i := strings.LastIndex(resource, "@"); i >= 0 {
+			return resource[:len("acct:")] + "*" + resource[i:]
+		}
+	}
+
+	u, err := url.Parse(resource)
+	if err != nil || u.Scheme == "" {
+		return "*"
+	}
+
+	if u.Host != "" {
+		return u.Scheme + "://" + u.Host + "/*"
+	}
+
+	return u.Scheme + ":*"
+}
+
+func redactString(s, resource string, redact func(string) string) string {
+	if redact == nil || resource == "" {
+		return s
+	}
+
+	redacted := redact(resource)
+	if redacted == resource {
+		return s
+	}
+
+	quoted, quotedRedacted := strconv.Quote(resource), strconv.Quote(redacted)
+
+	s = strings.ReplaceAll(s, url.QueryEscape(resource), url.QueryEscape(redacted))
+	s = strings.ReplaceAll(s, url.PathEscape(resource), url.PathEscape(redacted))
+	s = strings.ReplaceAll(s, quoted[1:len(quoted)-1], quotedRedacted[1:len(quotedRedacted)-1])
+	return strings.ReplaceAll(s, resource, redacted)
+}
+
+func logAttrs(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, attrs ...slog.Attr) {
+	if logger == nil || !logger.Enabled(ctx, level) {
+		return
+	}
+
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func (client *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logAttrs(ctx, client.Logger, level, msg, attrs...)
+}
+
+func (client *Client) redact(s, resource string) string {
+	return redactString(s, resource, client.RedactResource)
+}
+
+func (handler *Handler) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logAttrs(ctx, handler.Logger, level, msg, attrs...)
+}
+
+func (handler *Handler) redact(resource string) string {
+	if handler.RedactResource == nil {
+		return resource
+	}
+
+	return handler.RedactResource(resource)
+}
@@ -0,0 +1,152 @@
+package webfinger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_MaskResource(t *testing.T) {
+	tests := []struct {
+		Resource string
+		Expected string
+	}{
+		{Resource: "acct:test@example.com", Expected: "acct:*@example.com"},
+		{Resource: "acct:test@sub@example.com", Expected: "acct:*@example.com"},
+		{Resource: "https://example.com/users/test?q=1", Expected: "https://example.com/*"},
+		{Resource: "mailto:test@example.com", Expected: "mailto:*"},
+		{Resource: "test", Expected: "*"},
+	}
+
+	for i, test := range tests {
+		actual := webfinger.MaskResource(test.Resource)
+		if test.Expected != actual {
+			t.Logf("case_index: %d, expected: %s, actual: %s", i, test.Expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_Logger(t *testing.T) {
+	var testServerURL string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/webfinger" {
+			http.Redirect(w, r, testServerURL+"/webfinger?"+r.URL.RawQuery, http.StatusFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jrd+json; charset=utf-8")
+		io.WriteString(w, `{"subject":"acct:other@example.com"}`)
+	}))
+	defer testServer.Close()
+
+	testServerURL = testServer.URL
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var buf bytes.Buffer
+	client := &webfinger.Client{
+		HTTPClient:     http.DefaultClient,
+		HTTPMode:       true,
+		ValidationMode: webfinger.ValidationStrict,
+		Logger:         slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		RedactResource: webfinger.MaskResource,
+	}
+
+	_, err = client.DoContext(context.Background(), &webfinger.Request{
+		Host:     u.Host,
+		Resource: "acct:secret@example.com",
+	})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	logs := buf.String()
+	for _, expected := range []string{
+		`level=DEBUG msg="webfinger request"`,
+		`level=DEBUG msg="webfinger response"`,
+		"content_type=application/jrd+json",
+		"redirects=",
+		`level=WARN msg="webfinger protocol violation"`,
+		"acct%3A%2A%40example.com",
+	} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("log does not contain %q: %s", expected, logs)
+		}
+	}
+
+	if strings.Contains(logs, "secret") {
+		t.Errorf("log contains resource: %s", logs)
+	}
+}
+
+func Test_Handler_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := &webfinger.Handler{
+		Resolver: webfinger.ResolverFunc(func(ctx context.Context, resource string, rels []string) (*webfinger.Message, error) {
+			return nil, errors.New("backend unavailable for " + resource)
+		}),
+		Logger:         slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		RedactResource: webfinger.MaskResource,
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/webfinger?resource=acct%3Asecret%40example.com", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("unexpected status code: %d", w.Code)
+	}
+
+	logs := buf.String()
+	for _, expected := range []string{
+		`level=DEBUG msg="webfinger request" resource=acct:*@example.com`,
+		`level=WARN msg="webfinger resolver failed"`,
+	} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("log does not contain %q: %s", expected, logs)
+		}
+	}
+
+	if strings.Contains(logs, "secret") {
+		t.Errorf("log contains resource: %s", logs)
+	}
+}
+
+func Test_Handler_Logger_InvalidQuery(t *testing.T) {
+	var buf bytes.Buffer
+	handler := &webfinger.Handler{
+		Resolver: webfinger.ResolverFunc(func(ctx context.Context, resource string, rels []string) (*webfinger.Message, error) {
+			return nil, webfinger.ErrResourceNotFound
+		}),
+		Logger:         slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		RedactResource: webfinger.MaskResource,
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/webfinger?resource=acct%3Asecret%40example.com%7F", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status code: %d", w.Code)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, `msg="webfinger invalid query"`) {
+		t.Errorf("log does not contain invalid query record: %s", logs)
+	}
+
+	if strings.Contains(logs, "secret") {
+		t.Errorf("log contains resource: %s", logs)
+	}
+}
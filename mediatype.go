@@ -0,0 +1,158 @@
+package webfinger
+
+import (
+	"mime"
+	"slices"
+	"strings"
+)
+
+type MediaTypeMatch int
+
+const (
+	MatchBaseType MediaTypeMatch = iota
+	MatchExactParameters
+	MatchParameterSubset
+)
+
+const ianaRelationPrefix = "http://www.iana.org/assignments/relation/"
+
+// MediaTypeMatches compares the profile parameter as a whitespace separated set.
+func MediaTypeMatches(mediaType, want string, match MediaTypeMatch) bool {
+	t, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+
+	wantType, wantParams, err := mime.ParseMediaType(want)
+	if err != nil {
+		return false
+	}
+
+	if t != wantType {
+		return false
+	}
+
+	switch match {
+	case MatchBaseType:
+		return true
+	case MatchExactParameters:
+		if len(params) != len(wantParams) {
+			return false
+		}
+
+		for name, value := range wantParams {
+			v, ok := params[name]
+			if !ok || !parameterMatches(name, v, value, true) {
+				return false
+			}
+		}
+
+		return true
+	case MatchParameterSubset:
+		for name, value := range wantParams {
+			v, ok := params[name]
+			if !ok || !parameterMatches(name, v, value, false) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func parameterMatches(name, value, want string, exact bool) bool {
+	switch name {
+	case "charset":
+		return strings.EqualFold(value, want)
+	case "profile":
+		profiles := strings.Fields(value)
+		wantProfiles := strings.Fields(want)
+		for _, p := range wantProfiles {
+			if !slices.Contains(profiles, p) {
+				return false
+			}
+		}
+
+		if exact {
+			for _, p := range profiles {
+				if !slices.Contains(wantProfiles, p) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	return value == want
+}
+
+// RelationTypesEqual folds registered relation names with their IANA URI form.
+func RelationTypesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	a = registeredRelationName(a)
+	b = registeredRelationName(b)
+	if strings.Contains(a, ":") || strings.Contains(b, ":") {
+		return false
+	}
+
+	return strings.EqualFold(a, b)
+}
+
+func registeredRelationName(rel string) string {
+	if len(rel) > len(ianaRelationPrefix) && strings.EqualFold(rel[:len(ianaRelationPrefix)], ianaRelationPrefix) {
+		name := rel[len(ianaRelationPrefix):]
+		if !strings.ContainsAny(name, ":/?#") {
+			return name
+		}
+	}
+
+	return rel
+}
+
+func (r Message) GetLinkByMediaType(t string, match MediaTypeMatch) *Link {
+	for _, link := range r.Links {
+		if MediaTypeMatches(link.Type, t, match) {
+			return &link
+		}
+	}
+
+	return nil
+}
+
+func (r Message) GetLinksByMediaType(t string, match MediaTypeMatch) []Link {
+	result := make([]Link, 0)
+	for _, link := range r.Links {
+		if MediaTypeMatches(link.Type, t, match) {
+			result = append(result, link)
+		}
+	}
+
+	return result
+}
+
+func (r Message) GetLinkByRelationAndMediaType(rel, t string, match MediaTypeMatch) *Link {
+	for _, link := range r.Links {
+		if RelationTypesEqual(link.Rel, rel) && MediaTypeMatches(link.Type, t, match) {
+			return &link
+		}
+	}
+
+	return nil
+}
+
+func (r Message) GetLinksByRelationAndMediaType(rel, t string, match MediaTypeMatch) []Link {
+	result := make([]Link, 0)
+	for _, link := range r.Links {
+		if RelationTypesEqual(link.Rel, rel) && MediaTypeMatches(link.Type, t, match) {
+			result = append(result, link)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package webfinger_test
+
+import (
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_MediaTypeMatches(t *testing.T) {
+	tests := []struct {
+		MediaType string
+		Want      string
+		Match     webfinger.MediaTypeMatch
+		Expected  bool
+	}{
+		{MediaType: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, Want: "application/ld+json", Match: webfinger.MatchBaseType, Expected: true},
+		{MediaType: "Application/JSON", Want: "application/json", Match: webfinger.MatchBaseType, Expected: true},
+		{MediaType: "text/html", Want: "application/json", Match: webfinger.MatchBaseType, Expected: false},
+		{MediaType: "", Want: "application/json", Match: webfinger.MatchBaseType, Expected: false},
+		{MediaType: `application/ld+json ;profile = "https://www.w3.org/ns/activitystreams"`, Want: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, Match: webfinger.MatchExactParameters, Expected: true},
+		{MediaType: `text/plain; charset=UTF-8; format=flowed`, Want: `text/plain; format=flowed; charset=utf-8`, Match: webfinger.MatchExactParameters, Expected: true},
+		{MediaType: `text/plain; charset=utf-8; format=flowed`, Want: `text/plain; charset=utf-8`, Match: webfinger.MatchExactParameters, Expected: false},
+		{MediaType: `application/ld+json; profile="https://example.com/a https://example.com/b"`, Want: `application/ld+json; profile="https://example.com/b  https://example.com/a"`, Match: webfinger.MatchExactParameters, Expected: true},
+		{MediaType: `text/plain; charset=utf-8; format=flowed`, Want: `text/plain; charset=utf-8`, Match: webfinger.MatchParameterSubset, Expected: true},
+		{MediaType: `text/plain; charset=utf-8`, Want: `text/plain; charset=utf-8; format=flowed`, Match: webfinger.MatchParameterSubset, Expected: false},
+		{MediaType: `application/ld+json; profile="https://example.com/a https://www.w3.org/ns/activitystreams"`, Want: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, Match: webfinger.MatchParameterSubset, Expected: true},
+		{MediaType: `application/ld+json; profile="https://example.com/a"`, Want: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, Match: webfinger.MatchParameterSubset, Expected: false},
+	}
+
+	for i, test := range tests {
+		actual := webfinger.MediaTypeMatches(test.MediaType, test.Want, test.Match)
+		if test.Expected != actual {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, test.Expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func Test_RelationTypesEqual(t *testing.T) {
+	tests := []struct {
+		A        string
+		B        string
+		Expected bool
+	}{
+		{A: "self", B: "self", Expected: true},
+		{A: "Self", B: "self", Expected: true},
+		{A: "http://www.iana.org/assignments/relation/self", B: "self", Expected: true},
+		{A: "alternate", B: "self", Expected: false},
+		{A: "http://webfinger.net/rel/profile-page", B: "http://webfinger.net/rel/profile-page", Expected: true},
+		{A: "http://webfinger.net/rel/profile-page", B: "HTTP://WEBFINGER.NET/rel/profile-page", Expected: false},
+		{A: "http://webfinger.net/rel/profile-page", B: "profile-page", Expected: false},
+	}
+
+	for i, test := range tests {
+		actual := webfinger.RelationTypesEqual(test.A, test.B)
+		if test.Expected != actual {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, test.Expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Message_GetLinksByRelationAndMediaType(t *testing.T) {
+	message := webfinger.Message{
+		Links: []webfinger.Link{
+			{Rel: "self", Type: "text/html", Href: "https://example.com/@test"},
+			{Rel: "self", Type: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`, Href: "https://example.com/users/test"},
+			{Rel: "http://www.iana.org/assignments/relation/self", Type: "application/activity+json", Href: "https://example.com/actor/test"},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: "https://example.com/profile/test"},
+		},
+	}
+
+	links := message.GetLinksByRelationAndMediaType("self", "application/ld+json", webfinger.MatchBaseType)
+	if len(links) != 1 || links[0].Href != "https://example.com/users/test" {
+		t.Errorf("unexpected links: %v", links)
+	}
+
+	link := message.GetLinkByRelationAndMediaType("self", "application/activity+json", webfinger.MatchExactParameters)
+	if link == nil || link.Href != "https://example.com/actor/test" {
+		t.Errorf("unexpected link: %v", link)
+	}
+
+	links = message.GetLinksByMediaType("text/html", webfinger.MatchBaseType)
+	if len(links) != 2 {
+		t.Errorf("unexpected links: %v", links)
+	}
+
+	link = message.GetLinkByMediaType(`application/ld+json;profile="https://www.w3.org/ns/activitystreams"`, webfinger.MatchExactParameters)
+	if link == nil || link.Href != "https://example.com/users/test" {
+		t.Errorf("unexpected link: %v", link)
+	}
+
+	if link := message.GetLinkByRelationAndMediaType("alternate", "text/html", webfinger.MatchBaseType); link != nil {
+		t.Errorf("unexpected link: %v", link)
+	}
+}
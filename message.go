@@ -1,8 +1,12 @@
 package webfinger
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"maps"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/MitarashiDango/go-nullable"
 )
@@ -10,6 +14,7 @@ import (
 type Properties map[string]nullable.String
 
 type Message struct {
+	Expires    *time.Time `json:"expires,omitempty"`
 	Subject    string     `json:"subject"`
 	Aliases    []string   `json:"aliases,omitempty"`
 	Properties Properties `json:"properties,omitempty"`
@@ -17,9 +22,147 @@ type Message struct {
 }
 
 type Link struct {
-	Rel  string `json:"rel,omitempty" xml:"rel,attr,omitempty"`
-	Type string `json:"type,omitempty" xml:"type,attr,omitempty"`
-	Href string `json:"href,omitempty" xml:"href,attr,omitempty"`
+	Rel        string            `json:"rel,omitempty"`
+	Type       string            `json:"type,omitempty"`
+	Href       string            `json:"href,omitempty"`
+	Titles     map[string]string `json:"titles,omitempty"`
+	Properties Properties        `json:"properties,omitempty"`
+	Template   string            `json:"template,omitempty"`
+}
+
+const undeterminedLanguage = "und"
+
+type xmlProperty struct {
+	Type     string `xml:"type,attr"`
+	Nil      bool   `xml:"http://www.w3.org/2001/XMLSchema-instance nil,attr"`
+	Nullable bool   `xml:"nillable,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type xmlMarshalProperty struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Nil   bool   `xml:"xsi:nil,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type xmlTitle struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+func propertiesFromXML(src []xmlProperty) Properties {
+	properties := map[string]nullable.String{}
+	for _, v := range src {
+		var str nullable.String
+		if v.Nil || v.Nullable {
+			str.SetNull()
+		} else {
+			str.SetValue(v.Value)
+		}
+		properties[v.Type] = str
+	}
+
+	return properties
+}
+
+func propertiesToXML(properties Properties) []xmlMarshalProperty {
+	mapKeys := make([]string, 0, len(properties))
+	for k := range properties {
+		mapKeys = append(mapKeys, k)
+	}
+	slices.Sort(mapKeys)
+
+	dst := make([]xmlMarshalProperty, 0, len(mapKeys))
+	for _, k := range mapKeys {
+		v := properties[k]
+
+		if v.IsNull() {
+			dst = append(dst, xmlMarshalProperty{
+				Type: k,
+				Nil:  true,
+			})
+		} else {
+			dst = append(dst, xmlMarshalProperty{
+				Type:  k,
+				Nil:   false,
+				Value: v.Value(),
+			})
+		}
+	}
+
+	return dst
+}
+
+func (l *Link) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var src struct {
+		Rel        string        `xml:"rel,attr"`
+		Type       string        `xml:"type,attr"`
+		Href       string        `xml:"href,attr"`
+		Template   string        `xml:"template,attr"`
+		Titles     []xmlTitle    `xml:"Title"`
+		Properties []xmlProperty `xml:"Property"`
+	}
+
+	if err := d.DecodeElement(&src, &start); err != nil {
+		return err
+	}
+
+	var titles map[string]string
+	if len(src.Titles) > 0 {
+		titles = make(map[string]string, len(src.Titles))
+		for _, v := range src.Titles {
+			lang := v.Lang
+			if lang == "" {
+				lang = undeterminedLanguage
+			}
+			titles[lang] = v.Value
+		}
+	}
+
+	var properties Properties
+	if len(src.Properties) > 0 {
+		properties = propertiesFromXML(src.Properties)
+	}
+
+	l.Rel, l.Type, l.Href, l.Template, l.Titles, l.Properties = src.Rel, src.Type, src.Href, src.Template, titles, properties
+
+	return nil
+}
+
+func (l Link) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "Link"
+
+	var src struct {
+		Rel        string               `xml:"rel,attr,omitempty"`
+		Type       string               `xml:"type,attr,omitempty"`
+		Href       string               `xml:"href,attr,omitempty"`
+		Template   string               `xml:"template,attr,omitempty"`
+		Titles     []xmlTitle           `xml:"Title,omitempty"`
+		Properties []xmlMarshalProperty `xml:"Property,omitempty"`
+	}
+
+	src.Rel, src.Type, src.Href, src.Template = l.Rel, l.Type, l.Href, l.Template
+
+	mapKeys := make([]string, 0, len(l.Titles))
+	for k := range l.Titles {
+		mapKeys = append(mapKeys, k)
+	}
+	slices.Sort(mapKeys)
+
+	for _, k := range mapKeys {
+		lang := k
+		if lang == undeterminedLanguage {
+			lang = ""
+		}
+		src.Titles = append(src.Titles, xmlTitle{
+			Lang:  lang,
+			Value: l.Titles[k],
+		})
+	}
+
+	src.Properties = propertiesToXML(l.Properties)
+
+	return e.EncodeElement(src, start)
 }
 
 func (r Message) GetLinkByType(t string) *Link {
@@ -64,46 +207,111 @@ func (r Message) GetLinksByRelationType(t string) []Link {
 	return result
 }
 
-func (r *Message) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+func (r Message) GetLinksByRelationTypes(ts ...string) []Link {
+	result := make([]Link, 0)
+	for _, link := range r.Links {
+		if slices.Contains(ts, link.Rel) {
+			result = append(result, link)
+		}
+	}
+
+	return result
+}
+
+func (r Message) clone() *Message {
+	dst := r
+	if r.Expires != nil {
+		expires := *r.Expires
+		dst.Expires = &expires
+	}
+	dst.Aliases = slices.Clone(r.Aliases)
+	dst.Properties = maps.Clone(r.Properties)
+	if r.Links != nil {
+		dst.Links = make([]Link, len(r.Links))
+		for i, link := range r.Links {
+			link.Titles = maps.Clone(link.Titles)
+			link.Properties = maps.Clone(link.Properties)
+			dst.Links[i] = link
+		}
+	}
+
+	return &dst
+}
+
+func (r *Message) UnmarshalJSON(b []byte) error {
+	type message Message
 	var src struct {
-		Subject    string   `xml:"Subject"`
-		Aliases    []string `xml:"Alias"`
-		Properties []struct {
-			Type     string `xml:"type,attr"`
-			Nil      bool   `xml:"http://www.w3.org/2001/XMLSchema-instance nil,attr"`
-			Nullable bool   `xml:"nillable,attr"`
-			Value    string `xml:",chardata"`
-		} `xml:"Property"`
-		Links []Link `xml:"Link"`
+		message
+		Expires json.RawMessage `json:"expires"`
 	}
 
-	if err := d.DecodeElement(&src, &start); err != nil {
+	if err := json.Unmarshal(b, &src); err != nil {
 		return err
 	}
 
-	properties := map[string]nullable.String{}
-	for _, v := range src.Properties {
-		var str nullable.String
-		if v.Nil || v.Nullable {
-			str.SetNull()
-		} else {
-			str.SetValue(v.Value)
-		}
-		properties[v.Type] = str
+	*r = Message(src.message)
+	r.Expires = nil
+
+	var expires string
+	if json.Unmarshal(src.Expires, &expires) == nil {
+		r.Expires = parseExpires(expires)
 	}
 
-	r.Subject, r.Aliases, r.Properties, r.Links = src.Subject, src.Aliases, properties, src.Links
+	return nil
+}
+
+// parseExpires accepts xs:dateTime values with or without a zone and treats
+// anything else as absent, since expires is only a cache hint.
+func parseExpires(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
 
 	return nil
 }
 
-func (r Message) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	type tmpProperties struct {
-		Type  string `xml:"type,attr,omitempty"`
-		Nil   bool   `xml:"xsi:nil,attr,omitempty"`
-		Value string `xml:",chardata"`
+func (r *Message) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return r.unmarshalXML(d, start, &DefaultDecodeLimits)
+}
+
+func (r *Message) unmarshalXML(d *xml.Decoder, start xml.StartElement, limits *DecodeLimits) error {
+	var src struct {
+		Expires    string        `xml:"Expires"`
+		Subject    string        `xml:"Subject"`
+		Aliases    []string      `xml:"Alias"`
+		Properties []xmlProperty `xml:"Property"`
+		Links      []Link        `xml:"Link"`
+	}
+
+	ld := xml.NewTokenDecoder(&limitedTokenReader{
+		decoder: d,
+		limits:  limits,
+		start:   &start,
+	})
+
+	token, err := ld.Token()
+	if err != nil {
+		return err
 	}
 
+	limitedStart := token.(xml.StartElement)
+	if err := ld.DecodeElement(&src, &limitedStart); err != nil {
+		return err
+	}
+
+	r.Expires, r.Subject, r.Aliases, r.Properties, r.Links = parseExpires(src.Expires), src.Subject, src.Aliases, propertiesFromXML(src.Properties), src.Links
+
+	return nil
+}
+
+func (r Message) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	start.Name.Local = "XRD"
 	start.Name.Space = "http://docs.oasis-open.org/ns/xri/xrd-1.0"
 	start.Attr = append(start.Attr, xml.Attr{
@@ -114,39 +322,21 @@ func (r Message) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	})
 
 	var src struct {
-		Subject    string          `xml:"Subject"`
-		Aliases    []string        `xml:"Alias,omitempty"`
-		Properties []tmpProperties `xml:"Property,omitempty"`
-		Links      []Link          `xml:"Link,omitempty"`
+		Expires    string               `xml:"Expires,omitempty"`
+		Subject    string               `xml:"Subject"`
+		Aliases    []string             `xml:"Alias,omitempty"`
+		Properties []xmlMarshalProperty `xml:"Property,omitempty"`
+		Links      []Link               `xml:"Link,omitempty"`
 	}
 
+	if r.Expires != nil {
+		src.Expires = r.Expires.UTC().Format(time.RFC3339)
+	}
 	src.Subject = r.Subject
 	src.Aliases = r.Aliases
 	src.Links = r.Links
 
-	mapKeys := make([]string, 0, len(r.Properties))
-	for k := range r.Properties {
-		mapKeys = append(mapKeys, k)
-	}
-	slices.Sort(mapKeys)
-
-	src.Properties = make([]tmpProperties, 0, len(mapKeys))
-	for _, k := range mapKeys {
-		v := r.Properties[k]
-
-		if v.IsNull() {
-			src.Properties = append(src.Properties, tmpProperties{
-				Type: k,
-				Nil:  true,
-			})
-		} else {
-			src.Properties = append(src.Properties, tmpProperties{
-				Type:  k,
-				Nil:   false,
-				Value: v.Value(),
-			})
-		}
-	}
+	src.Properties = propertiesToXML(r.Properties)
 
 	return e.EncodeElement(src, start)
 }
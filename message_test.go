@@ -3,7 +3,10 @@ package webfinger
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/MitarashiDango/go-nullable"
 )
@@ -231,6 +234,55 @@ func Test_Message_GetLinksByRelationType_NotExists(t *testing.T) {
 	}
 }
 
+func Test_Message_GetLinksByRelationTypes_Exists(t *testing.T) {
+	m := Message{
+		Subject: "acct:test@localhost",
+		Links: []Link{
+			{
+				Rel:  "http://webfinger.net/rel/profile-page",
+				Type: "text/html",
+				Href: "http://localhost/@test",
+			},
+			{
+				Rel:  "self",
+				Type: "application/activity+json",
+				Href: "http://localhost/users/test",
+			},
+			{
+				Rel:  "http://ostatus.org/schema/1.0/subscribe",
+				Href: "http://localhost/authorize_interaction",
+			},
+		},
+	}
+
+	links := m.GetLinksByRelationTypes("self", "http://webfinger.net/rel/profile-page")
+	if len(links) != 2 {
+		t.FailNow()
+	} else if links[0].Href != "http://localhost/@test" {
+		t.FailNow()
+	} else if links[1].Href != "http://localhost/users/test" {
+		t.FailNow()
+	}
+}
+
+func Test_Message_GetLinksByRelationTypes_NotExists(t *testing.T) {
+	m := Message{
+		Subject: "acct:test@localhost",
+		Links: []Link{
+			{
+				Rel:  "http://webfinger.net/rel/profile-page",
+				Type: "text/html",
+				Href: "http://localhost/@test",
+			},
+		},
+	}
+
+	links := m.GetLinksByRelationTypes("self")
+	if len(links) != 0 {
+		t.FailNow()
+	}
+}
+
 func Test_Message_UnmarshalXML_001(t *testing.T) {
 	xmlString := `<?xml version='1.0'?>
 <XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
@@ -473,3 +525,220 @@ func Test_Message_MarshalJSON_001(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func Test_Link_UnmarshalXML_001(t *testing.T) {
+	xmlString := `<?xml version='1.0'?>
+<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<Subject>acct:test@localhost</Subject>
+<Link rel="http://webfinger.net/rel/profile-page" type="text/html" href="http://localhost/@test">
+<Title xml:lang="en-us">Profile</Title>
+<Title>Profile (und)</Title>
+<Property type="http://localhost/ns/testtype1">teststring1</Property>
+<Property type="http://localhost/ns/testtype2" xsi:nil="true" />
+</Link>
+<Link rel="http://ostatus.org/schema/1.0/subscribe" template="http://localhost/authorize_interaction?uri={uri}"/>
+</XRD>`
+
+	var message Message
+
+	if err := xml.Unmarshal([]byte(xmlString), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(message.Links) != 2 {
+		t.FailNow()
+	}
+
+	if message.Links[0].Titles["en-us"] != "Profile" {
+		t.FailNow()
+	}
+
+	if message.Links[0].Titles["und"] != "Profile (und)" {
+		t.FailNow()
+	}
+
+	if !message.Links[0].Properties["http://localhost/ns/testtype1"].Equal(nullable.NewString("teststring1")) {
+		t.FailNow()
+	}
+
+	if v, ok := message.Links[0].Properties["http://localhost/ns/testtype2"]; !ok || !v.IsNull() {
+		t.FailNow()
+	}
+
+	if len(message.Properties) != 0 {
+		t.FailNow()
+	}
+
+	if message.Links[1].Template != "http://localhost/authorize_interaction?uri={uri}" {
+		t.FailNow()
+	}
+
+	if message.Links[1].Titles != nil || message.Links[1].Properties != nil {
+		t.FailNow()
+	}
+}
+
+func Test_Link_MarshalXML_001(t *testing.T) {
+	expected := `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><Subject>acct:test@localhost</Subject><Link rel="http://webfinger.net/rel/profile-page" type="text/html" href="http://localhost/@test"><Title xml:lang="en-us">Profile</Title><Title>Profile (und)</Title><Property type="http://localhost/ns/testtype1">teststring1</Property><Property type="http://localhost/ns/testtype2" xsi:nil="true"></Property></Link><Link rel="http://ostatus.org/schema/1.0/subscribe" template="http://localhost/authorize_interaction?uri={uri}"></Link></XRD>`
+	message := &Message{
+		Subject: "acct:test@localhost",
+		Links: []Link{
+			{
+				Rel:  "http://webfinger.net/rel/profile-page",
+				Type: "text/html",
+				Href: "http://localhost/@test",
+				Titles: map[string]string{
+					"en-us": "Profile",
+					"und":   "Profile (und)",
+				},
+				Properties: Properties{
+					"http://localhost/ns/testtype1": nullable.NewString("teststring1"),
+					"http://localhost/ns/testtype2": {},
+				},
+			},
+			{
+				Rel:      "http://ostatus.org/schema/1.0/subscribe",
+				Template: "http://localhost/authorize_interaction?uri={uri}",
+			},
+		},
+	}
+
+	b, err := xml.Marshal(message)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != expected {
+		t.Fatalf("unexpected xml: %s", b)
+	}
+
+	var actual Message
+	if err := xml.Unmarshal(b, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.Links[0].Titles["en-us"] != "Profile" || actual.Links[0].Titles["und"] != "Profile (und)" {
+		t.FailNow()
+	}
+
+	if !actual.Links[0].Properties["http://localhost/ns/testtype2"].IsNull() {
+		t.FailNow()
+	}
+
+	if actual.Links[1].Template != message.Links[1].Template {
+		t.FailNow()
+	}
+}
+
+func Test_Link_MarshalJSON_001(t *testing.T) {
+	expected := `{"subject":"acct:test@localhost","links":[{"rel":"http://webfinger.net/rel/profile-page","type":"text/html","href":"http://localhost/@test","titles":{"en-us":"Profile","und":"Profile (und)"},"properties":{"http://localhost/ns/testtype1":"teststring1","http://localhost/ns/testtype2":null}},{"rel":"http://ostatus.org/schema/1.0/subscribe","template":"http://localhost/authorize_interaction?uri={uri}"}]}`
+	message := &Message{
+		Subject: "acct:test@localhost",
+		Links: []Link{
+			{
+				Rel:  "http://webfinger.net/rel/profile-page",
+				Type: "text/html",
+				Href: "http://localhost/@test",
+				Titles: map[string]string{
+					"en-us": "Profile",
+					"und":   "Profile (und)",
+				},
+				Properties: Properties{
+					"http://localhost/ns/testtype1": nullable.NewString("teststring1"),
+					"http://localhost/ns/testtype2": {},
+				},
+			},
+			{
+				Rel:      "http://ostatus.org/schema/1.0/subscribe",
+				Template: "http://localhost/authorize_interaction?uri={uri}",
+			},
+		},
+	}
+
+	b, err := json.Marshal(message)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != expected {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	var actual Message
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.Links[0].Titles["en-us"] != "Profile" {
+		t.FailNow()
+	}
+
+	if !actual.Links[0].Properties["http://localhost/ns/testtype2"].IsNull() {
+		t.FailNow()
+	}
+
+	if actual.Links[1].Template != message.Links[1].Template {
+		t.FailNow()
+	}
+}
+
+func Test_Message_UnmarshalXML_DecodeLimits(t *testing.T) {
+	xmlString := `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Subject>acct:test@localhost</Subject>` + strings.Repeat("<a>", 64) + strings.Repeat("</a>", 64) + `</XRD>`
+
+	var message Message
+
+	var decodeLimitError *DecodeLimitError
+	if err := xml.Unmarshal([]byte(xmlString), &message); !errors.As(err, &decodeLimitError) {
+		t.Fatal(err)
+	}
+
+	if decodeLimitError.Name != "xml depth" || decodeLimitError.Limit != DefaultDecodeLimits.MaxXMLDepth {
+		t.FailNow()
+	}
+}
+
+func Test_Message_Expires_Lenient(t *testing.T) {
+	expected := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		JSON     string
+		XML      string
+		Expected *time.Time
+	}{
+		{JSON: `"2030-01-01T00:00:00Z"`, XML: `2030-01-01T00:00:00Z`, Expected: &expected},
+		{JSON: `"2030-01-01T00:00:00"`, XML: `2030-01-01T00:00:00`, Expected: &expected},
+		{JSON: `" 2030-01-01T00:00:00.000Z "`, XML: ` 2030-01-01T00:00:00.000Z `, Expected: &expected},
+		{JSON: `""`, XML: ``, Expected: nil},
+		{JSON: `"2024-01-01"`, XML: `2024-01-01`, Expected: nil},
+		{JSON: `1700000000`, XML: `tomorrow`, Expected: nil},
+		{JSON: `null`, XML: ``, Expected: nil},
+	}
+
+	for i, test := range tests {
+		var jsonMessage Message
+		if err := json.Unmarshal([]byte(`{"subject":"acct:test@example.com","expires":`+test.JSON+`}`), &jsonMessage); err != nil {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+			continue
+		}
+
+		var xmlMessage Message
+		if err := xml.Unmarshal([]byte(`<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Expires>`+test.XML+`</Expires><Subject>acct:test@example.com</Subject></XRD>`), &xmlMessage); err != nil {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+			continue
+		}
+
+		for _, message := range []Message{jsonMessage, xmlMessage} {
+			if message.Subject != "acct:test@example.com" {
+				t.Logf("case_index: %d, unexpected subject: %s", i, message.Subject)
+				t.Fail()
+			}
+
+			if (test.Expected == nil) != (message.Expires == nil) || (test.Expected != nil && !test.Expected.Equal(*message.Expires)) {
+				t.Logf("case_index: %d, expected: %v, actual: %v", i, test.Expected, message.Expires)
+				t.Fail()
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package webfinger
+
+import (
+	"net/http"
+)
+
+// Doer performs one exchange. Do must return the response it received, also
+// alongside an error, so that status, headers and 304 revalidation keep
+// working; the response body is already closed.
+type Doer interface {
+	Do(request *http.Request) (*Message, *http.Response, error)
+}
+
+type DoerFunc func(request *http.Request) (*Message, *http.Response, error)
+
+func (f DoerFunc) Do(request *http.Request) (*Message, *http.Response, error) {
+	return f(request)
+}
+
+// Middleware wraps every exchange made by the client, including retries and
+// host-meta fallback requests. The first middleware is the outermost.
+type Middleware func(next Doer) Doer
+
+func Chain(doer Doer, middleware ...Middleware) Doer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		doer = middleware[i](doer)
+	}
+
+	return doer
+}
+
+func (client *Client) doer() Doer {
+	return Chain(DoerFunc(client.exchange), client.Middleware...)
+}
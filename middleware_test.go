@@ -0,0 +1,169 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_Middleware(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Signature") != "signed" {
+			t.Errorf("unexpected header value: %s: %s", "Signature", r.Header.Get("Signature"))
+		}
+
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@example.com","links":[{"rel":"self","href":"https://example.com/users/test"}]}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	calls := []string{}
+	trace := func(name string) webfinger.Middleware {
+		return func(next webfinger.Doer) webfinger.Doer {
+			return webfinger.DoerFunc(func(request *http.Request) (*webfinger.Message, *http.Response, error) {
+				calls = append(calls, name+":request")
+				webFingerMessage, response, err := next.Do(request)
+				if err == nil && webFingerMessage.Subject != "acct:test@example.com" {
+					t.Errorf("unexpected subject: %s", webFingerMessage.Subject)
+				}
+				calls = append(calls, name+":response")
+				return webFingerMessage, response, err
+			})
+		}
+	}
+
+	sign := func(next webfinger.Doer) webfinger.Doer {
+		return webfinger.DoerFunc(func(request *http.Request) (*webfinger.Message, *http.Response, error) {
+			request.Header.Set("Signature", "signed")
+			return next.Do(request)
+		})
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		Middleware: []webfinger.Middleware{trace("outer"), trace("inner"), sign},
+	}
+
+	webFingerMessage, err := client.DoContext(context.Background(), &webfinger.Request{
+		Host:     u.Host,
+		Resource: "acct:test@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if webFingerMessage.Subject != "acct:test@example.com" {
+		t.Errorf("unexpected subject: %s", webFingerMessage.Subject)
+	}
+
+	expected := []string{"outer:request", "inner:request", "inner:response", "outer:response"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func Test_Client_Middleware_ShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		Middleware: []webfinger.Middleware{
+			func(next webfinger.Doer) webfinger.Doer {
+				return webfinger.DoerFunc(func(request *http.Request) (*webfinger.Message, *http.Response, error) {
+					if request.URL.Host == "blocked.example.com" {
+						return nil, nil, errDenied
+					}
+					return next.Do(request)
+				})
+			},
+			func(next webfinger.Doer) webfinger.Doer {
+				return webfinger.DoerFunc(func(request *http.Request) (*webfinger.Message, *http.Response, error) {
+					if request.URL.Host == "empty.example.com" {
+						return nil, nil, nil
+					}
+					return next.Do(request)
+				})
+			},
+		},
+	}
+
+	_, err := client.DoContext(context.Background(), &webfinger.Request{
+		Host:     "blocked.example.com",
+		Resource: "acct:test@blocked.example.com",
+	})
+	if !errors.Is(err, errDenied) {
+		t.Error(err)
+	}
+
+	_, err = client.DoContext(context.Background(), &webfinger.Request{
+		Host:     "empty.example.com",
+		Resource: "acct:test@empty.example.com",
+	})
+	if !errors.Is(err, webfinger.ErrInvalidResponse) {
+		t.Error(err)
+	}
+}
+
+func Test_Client_Middleware_Revalidation_WithoutResponse(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "no-cache")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@example.com"}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		Cache:      webfinger.NewLRUCache(0),
+		Middleware: []webfinger.Middleware{
+			func(next webfinger.Doer) webfinger.Doer {
+				return webfinger.DoerFunc(func(request *http.Request) (*webfinger.Message, *http.Response, error) {
+					webFingerMessage, response, err := next.Do(request)
+					if err != nil {
+						return nil, nil, err
+					}
+					return webFingerMessage, response, nil
+				})
+			},
+		},
+	}
+
+	for range 2 {
+		webFingerMessage, err := client.DoContext(context.Background(), &webfinger.Request{
+			Host:     u.Host,
+			Resource: "acct:test@example.com",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if webFingerMessage.Subject != "acct:test@example.com" {
+			t.Errorf("unexpected subject: %s", webFingerMessage.Subject)
+		}
+	}
+}
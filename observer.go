@@ -0,0 +1,234 @@
+package webfinger
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess         Outcome = "success"
+	OutcomeNotFound        Outcome = "not_found"
+	OutcomeClientError     Outcome = "client_error"
+	OutcomeServerError     Outcome = "server_error"
+	OutcomeRateLimited     Outcome = "rate_limited"
+	OutcomeTimeout         Outcome = "timeout"
+	OutcomeCanceled        Outcome = "canceled"
+	OutcomePolicy          Outcome = "policy"
+	OutcomeInvalidResponse Outcome = "invalid_response"
+	OutcomeNetworkError    Outcome = "network_error"
+)
+
+type TracePhase string
+
+const (
+	TracePhaseDNS     TracePhase = "dns"
+	TracePhaseConnect TracePhase = "connect"
+	TracePhaseTLS     TracePhase = "tls"
+)
+
+// Events carry only low-cardinality labels; the queried resource is never
+// included so that observers can use every field as a metric label.
+type LookupStartEvent struct {
+	Host string
+}
+
+type LookupEndEvent struct {
+	Host       string
+	Outcome    Outcome
+	StatusCode int
+	Elapsed    time.Duration
+	FromCache  bool
+}
+
+type TraceEvent struct {
+	Host     string
+	Phase    TracePhase
+	Duration time.Duration
+	Err      error
+}
+
+type RetryEvent struct {
+	Host       string
+	Attempt    int
+	StatusCode int
+	Delay      time.Duration
+}
+
+type CacheHitEvent struct {
+	Host        string
+	NotFound    bool
+	Revalidated bool
+}
+
+type DecodeFailureEvent struct {
+	Host        string
+	ContentType string
+}
+
+type Observer interface {
+	LookupStart(ctx context.Context, event LookupStartEvent)
+	LookupEnd(ctx context.Context, event LookupEndEvent)
+	Trace(ctx context.Context, event TraceEvent)
+	Retry(ctx context.Context, event RetryEvent)
+	CacheHit(ctx context.Context, event CacheHitEvent)
+	DecodeFailure(ctx context.Context, event DecodeFailureEvent)
+}
+
+type NopObserver struct{}
+
+func (NopObserver) LookupStart(context.Context, LookupStartEvent)     {}
+func (NopObserver) LookupEnd(context.Context, LookupEndEvent)         {}
+func (NopObserver) Trace(context.Context, TraceEvent)                 {}
+func (NopObserver) Retry(context.Context, RetryEvent)                 {}
+func (NopObserver) CacheHit(context.Context, CacheHitEvent)           {}
+func (NopObserver) DecodeFailure(context.Context, DecodeFailureEvent) {}
+
+func (client *Client) observer() Observer {
+	if client.Observer == nil {
+		return NopObserver{}
+	}
+
+	return client.Observer
+}
+
+func (client *Client) withClientTrace(request *http.Request) *http.Request {
+	if client.Observer == nil {
+		return request
+	}
+
+	ctx := request.Context()
+	host := request.URL.Host
+	observer := client.Observer
+
+	var dnsStart, tlsStart time.Time
+	var mutex sync.Mutex
+	connectStart := map[string]time.Time{}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			observer.Trace(ctx, TraceEvent{
+				Host:     host,
+				Phase:    TracePhaseDNS,
+				Duration: time.Since(dnsStart),
+				Err:      info.Err,
+			})
+		},
+		ConnectStart: func(network, addr string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			connectStart[network+" "+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mutex.Lock()
+			start := connectStart[network+" "+addr]
+			mutex.Unlock()
+
+			observer.Trace(ctx, TraceEvent{
+				Host:     host,
+				Phase:    TracePhaseConnect,
+				Duration: time.Since(start),
+				Err:      err,
+			})
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			observer.Trace(ctx, TraceEvent{
+				Host:     host,
+				Phase:    TracePhaseTLS,
+				Duration: time.Since(tlsStart),
+				Err:      err,
+			})
+		},
+	}
+
+	return request.WithContext(httptrace.WithClientTrace(ctx, trace))
+}
+
+func lookupEndEvent(host string, result *LookupResult, err error, elapsed time.Duration) LookupEndEvent {
+	event := LookupEndEvent{
+		Host:    host,
+		Outcome: outcomeOf(err),
+		Elapsed: elapsed,
+	}
+
+	if result != nil {
+		event.StatusCode = result.StatusCode
+		event.FromCache = result.FromCache
+	}
+
+	var statusError *WebFingerResponseStatusError
+	if errors.As(err, &statusError) {
+		event.StatusCode = statusError.StatusCode
+	}
+
+	return event
+}
+
+func outcomeOf(err error) Outcome {
+	var statusError *WebFingerResponseStatusError
+	var rateLimitedError *RateLimitedError
+	var forbiddenDestinationError *ForbiddenDestinationError
+	var redirectError *RedirectError
+	var validationError *ValidationError
+	var bindingError *BindingError
+	var unsupportedContentTypeError *UnsupportedContentTypeError
+	var responseTooLargeError *ResponseTooLargeError
+	var decodeLimitError *DecodeLimitError
+	var policyError *PolicyError
+	var decodeError *DecodeError
+	var timeoutError *TimeoutError
+	var netError net.Error
+
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, ErrResourceNotFound):
+		return OutcomeNotFound
+	case errors.As(err, &rateLimitedError):
+		return OutcomeRateLimited
+	case errors.As(err, &statusError):
+		switch {
+		case statusError.StatusCode == http.StatusTooManyRequests:
+			return OutcomeRateLimited
+		case statusError.StatusCode >= 500:
+			return OutcomeServerError
+		}
+		return OutcomeClientError
+	case errors.Is(err, context.Canceled):
+		return OutcomeCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &timeoutError):
+		return OutcomeTimeout
+	case errors.As(err, &policyError),
+		errors.As(err, &forbiddenDestinationError),
+		errors.As(err, &redirectError),
+		errors.As(err, &validationError),
+		errors.As(err, &bindingError),
+		errors.Is(err, ErrInvalidResource):
+		return OutcomePolicy
+	case errors.As(err, &decodeError),
+		errors.As(err, &unsupportedContentTypeError),
+		errors.As(err, &responseTooLargeError),
+		errors.As(err, &decodeLimitError),
+		errors.Is(err, ErrInvalidResponse):
+		return OutcomeInvalidResponse
+	case errors.As(err, &netError) && netError.Timeout():
+		return OutcomeTimeout
+	case errors.As(err, &netError):
+		return OutcomeNetworkError
+	}
+
+	return OutcomeInvalidResponse
+}
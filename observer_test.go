@@ -0,0 +1,199 @@
+package webfinger_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+type recordingObserver struct {
+	mutex          sync.Mutex
+	starts         []webfinger.LookupStartEvent
+	ends           []webfinger.LookupEndEvent
+	traces         []webfinger.TraceEvent
+	retries        []webfinger.RetryEvent
+	cacheHits      []webfinger.CacheHitEvent
+	decodeFailures []webfinger.DecodeFailureEvent
+}
+
+func (o *recordingObserver) LookupStart(_ context.Context, event webfinger.LookupStartEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.starts = append(o.starts, event)
+}
+
+func (o *recordingObserver) LookupEnd(_ context.Context, event webfinger.LookupEndEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.ends = append(o.ends, event)
+}
+
+func (o *recordingObserver) Trace(_ context.Context, event webfinger.TraceEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.traces = append(o.traces, event)
+}
+
+func (o *recordingObserver) Retry(_ context.Context, event webfinger.RetryEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.retries = append(o.retries, event)
+}
+
+func (o *recordingObserver) CacheHit(_ context.Context, event webfinger.CacheHitEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.cacheHits = append(o.cacheHits, event)
+}
+
+func (o *recordingObserver) DecodeFailure(_ context.Context, event webfinger.DecodeFailureEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.decodeFailures = append(o.decodeFailures, event)
+}
+
+func Test_Client_Observer(t *testing.T) {
+	var attempts int
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resource") {
+		case "acct:html@example.com":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, "<html></html>")
+		case "acct:flaky@example.com":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fallthrough
+		default:
+			w.Header().Set("Content-Type", "application/jrd+json")
+			w.Header().Set("Cache-Control", "max-age=60")
+			io.WriteString(w, `{"subject":"`+r.URL.Query().Get("resource")+`"}`)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	observer := &recordingObserver{}
+	client := &webfinger.Client{
+		HTTPClient: testServer.Client(),
+		Cache:      webfinger.NewLRUCache(0),
+		RetryPolicy: &webfinger.RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+		},
+		Observer: observer,
+	}
+
+	for _, resource := range []string{"acct:test@example.com", "acct:test@example.com", "acct:html@example.com", "acct:flaky@example.com"} {
+		client.DoContext(context.Background(), &webfinger.Request{
+			Host:     u.Host,
+			Resource: resource,
+		})
+	}
+
+	if len(observer.starts) != 4 || len(observer.ends) != 4 {
+		t.Fatalf("unexpected lookup events: %v, %v", observer.starts, observer.ends)
+	}
+
+	expected := []webfinger.LookupEndEvent{
+		{Host: u.Host, Outcome: webfinger.OutcomeSuccess, StatusCode: http.StatusOK},
+		{Host: u.Host, Outcome: webfinger.OutcomeSuccess, StatusCode: http.StatusOK, FromCache: true},
+		{Host: u.Host, Outcome: webfinger.OutcomeInvalidResponse},
+		{Host: u.Host, Outcome: webfinger.OutcomeSuccess, StatusCode: http.StatusOK},
+	}
+	for i, event := range observer.ends {
+		event.Elapsed = 0
+		if event != expected[i] {
+			t.Errorf("case_index: %d, unexpected lookup end event: %v", i, event)
+		}
+	}
+
+	var phases []webfinger.TracePhase
+	for _, event := range observer.traces {
+		if event.Host != u.Host || event.Err != nil {
+			t.Errorf("unexpected trace event: %v", event)
+		}
+		phases = append(phases, event.Phase)
+	}
+
+	if !slices.Contains(phases, webfinger.TracePhaseConnect) || !slices.Contains(phases, webfinger.TracePhaseTLS) {
+		t.Errorf("unexpected trace phases: %v", phases)
+	}
+
+	if len(observer.retries) != 1 || observer.retries[0].StatusCode != http.StatusServiceUnavailable || observer.retries[0].Attempt != 1 {
+		t.Errorf("unexpected retry events: %v", observer.retries)
+	}
+
+	if len(observer.cacheHits) != 1 || observer.cacheHits[0] != (webfinger.CacheHitEvent{Host: u.Host}) {
+		t.Errorf("unexpected cache hit events: %v", observer.cacheHits)
+	}
+
+	if len(observer.decodeFailures) != 1 || observer.decodeFailures[0] != (webfinger.DecodeFailureEvent{Host: u.Host, ContentType: "text/html"}) {
+		t.Errorf("unexpected decode failure events: %v", observer.decodeFailures)
+	}
+}
+
+func Test_Client_Observer_Outcome(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resource") {
+		case "acct:missing@example.com":
+			w.WriteHeader(http.StatusNotFound)
+		case "acct:limited@example.com":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "acct:broken@example.com":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	tests := []struct {
+		Resource   string
+		Outcome    webfinger.Outcome
+		StatusCode int
+	}{
+		{Resource: "acct:missing@example.com", Outcome: webfinger.OutcomeNotFound},
+		{Resource: "acct:limited@example.com", Outcome: webfinger.OutcomeRateLimited, StatusCode: http.StatusTooManyRequests},
+		{Resource: "acct:broken@example.com", Outcome: webfinger.OutcomeServerError, StatusCode: http.StatusInternalServerError},
+		{Resource: "acct:bad@example.com", Outcome: webfinger.OutcomeClientError, StatusCode: http.StatusBadRequest},
+	}
+
+	for i, test := range tests {
+		observer := &recordingObserver{}
+		client := &webfinger.Client{
+			HTTPClient: http.DefaultClient,
+			HTTPMode:   true,
+			Observer:   observer,
+		}
+
+		client.DoContext(context.Background(), &webfinger.Request{
+			Host:     u.Host,
+			Resource: test.Resource,
+		})
+
+		if len(observer.ends) != 1 || observer.ends[0].Outcome != test.Outcome || observer.ends[0].StatusCode != test.StatusCode {
+			t.Logf("case_index: %d, unexpected lookup end events: %v", i, observer.ends)
+			t.Fail()
+		}
+	}
+}
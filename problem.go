@@ -0,0 +1,151 @@
+package webfinger
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultMaxErrorBodySize = 4 << 10
+
+type ProblemDetails struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// parseProblemDetails follows RFC 9457: members with an unexpected type are
+// ignored rather than failing the whole document.
+func parseProblemDetails(b []byte) *ProblemDetails {
+	var members map[string]any
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil
+	}
+
+	problem := &ProblemDetails{}
+	for name, value := range members {
+		switch name {
+		case "type":
+			problem.Type, _ = value.(string)
+		case "title":
+			problem.Title, _ = value.(string)
+		case "detail":
+			problem.Detail, _ = value.(string)
+		case "instance":
+			problem.Instance, _ = value.(string)
+		case "status":
+			if status, ok := value.(float64); ok && status == float64(int(status)) {
+				problem.Status = int(status)
+			}
+		default:
+			if problem.Extensions == nil {
+				problem.Extensions = map[string]any{}
+			}
+			problem.Extensions[name] = value
+		}
+	}
+
+	return problem
+}
+
+// parseRateLimit reads the RateLimit-* fields and the X-RateLimit-* variants.
+// Reset values may be delta seconds, Unix timestamps or RFC 3339 timestamps.
+func parseRateLimit(header http.Header, now time.Time) *RateLimit {
+	limit, hasLimit := headerInt(header, "RateLimit-Limit", "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(header, "RateLimit-Remaining", "X-RateLimit-Remaining")
+	reset, hasReset := headerReset(header, now, "RateLimit-Reset", "X-RateLimit-Reset")
+	if !hasLimit && !hasRemaining && !hasReset {
+		return nil
+	}
+
+	return &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}
+}
+
+func headerInt(header http.Header, keys ...string) (int, bool) {
+	for _, key := range keys {
+		if v, err := strconv.Atoi(strings.TrimSpace(header.Get(key))); err == nil && v >= 0 {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
+
+func headerReset(header http.Header, now time.Time, keys ...string) (time.Time, bool) {
+	for _, key := range keys {
+		value := strings.TrimSpace(header.Get(key))
+		if value == "" {
+			continue
+		}
+
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil && v >= 0 {
+			if v >= 1_000_000_000 {
+				return time.Unix(v, 0), true
+			}
+			return now.Add(time.Duration(v) * time.Second), true
+		}
+
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (client *Client) readErrorBody(response *http.Response) []byte {
+	limit := client.MaxErrorBodySize
+	if limit == 0 {
+		limit = DefaultMaxErrorBodySize
+	}
+
+	if limit < 0 {
+		return nil
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(response.Body, limit))
+	if len(b) == 0 {
+		return nil
+	}
+
+	return b
+}
+
+func (client *Client) newStatusError(response *http.Response) *WebFingerResponseStatusError {
+	statusError := &WebFingerResponseStatusError{
+		Host:        response.Request.URL.Host,
+		URL:         response.Request.URL.String(),
+		StatusCode:  response.StatusCode,
+		Status:      response.Status,
+		ContentType: contentType(response.Header),
+		Body:        client.readErrorBody(response),
+		RateLimit:   parseRateLimit(response.Header, time.Now()),
+	}
+
+	if retryAfter, ok := parseRetryAfter(response, time.Now()); ok {
+		statusError.RetryAfter = retryAfter
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type")); err == nil && mediaType == "application/problem+json" {
+		statusError.Problem = parseProblemDetails(statusError.Body)
+	}
+
+	return statusError
+}
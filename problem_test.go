@@ -0,0 +1,130 @@
+package webfinger_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_StatusErrorBody(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resource") {
+		case "acct:limited@example.com":
+			w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+			w.Header().Set("Retry-After", "30")
+			w.Header().Set("X-RateLimit-Limit", "300")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "2026-01-02T03:04:05Z")
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"type":"https://example.com/probs/rate-limit","title":"Too many requests","status":429,"detail":"Slow down","instance":123,"balance":30}`)
+		case "acct:broken@example.com":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("RateLimit-Remaining", "5")
+			w.Header().Set("RateLimit-Reset", "60")
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, strings.Repeat("x", 8192))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+	}
+
+	t.Run("problem", func(t *testing.T) {
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:limited@example.com"})
+
+		var statusError *webfinger.WebFingerResponseStatusError
+		if !errors.As(err, &statusError) {
+			t.Fatal(err)
+		}
+
+		if statusError.ContentType != "application/problem+json" || statusError.RetryAfter != 30*time.Second {
+			t.Errorf("unexpected status error: %v", statusError)
+		}
+
+		problem := statusError.Problem
+		if problem == nil || problem.Type != "https://example.com/probs/rate-limit" || problem.Title != "Too many requests" || problem.Status != http.StatusTooManyRequests || problem.Detail != "Slow down" || problem.Instance != "" {
+			t.Errorf("unexpected problem details: %v", problem)
+		}
+
+		if problem != nil && problem.Extensions["balance"] != float64(30) {
+			t.Errorf("unexpected problem extensions: %v", problem.Extensions)
+		}
+
+		rateLimit := statusError.RateLimit
+		if rateLimit == nil || rateLimit.Limit != 300 || rateLimit.Remaining != 0 || !rateLimit.Reset.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("unexpected rate limit: %v", rateLimit)
+		}
+
+		if !strings.HasSuffix(statusError.Error(), ": Slow down") {
+			t.Errorf("unexpected error message: %s", statusError.Error())
+		}
+	})
+
+	t.Run("body", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:broken@example.com"})
+
+		var statusError *webfinger.WebFingerResponseStatusError
+		if !errors.As(err, &statusError) {
+			t.Fatal(err)
+		}
+
+		if len(statusError.Body) != webfinger.DefaultMaxErrorBodySize || statusError.Problem != nil {
+			t.Errorf("unexpected status error: %d, %v", len(statusError.Body), statusError.Problem)
+		}
+
+		rateLimit := statusError.RateLimit
+		if rateLimit == nil || rateLimit.Remaining != 5 || rateLimit.Reset.Before(start.Add(60*time.Second)) {
+			t.Errorf("unexpected rate limit: %v", rateLimit)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:bad@example.com"})
+
+		var statusError *webfinger.WebFingerResponseStatusError
+		if !errors.As(err, &statusError) {
+			t.Fatal(err)
+		}
+
+		if statusError.Body != nil || statusError.RateLimit != nil || statusError.RetryAfter != 0 {
+			t.Errorf("unexpected status error: %v", statusError)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		client := &webfinger.Client{
+			HTTPClient:       http.DefaultClient,
+			HTTPMode:         true,
+			MaxErrorBodySize: -1,
+		}
+
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:limited@example.com"})
+
+		var statusError *webfinger.WebFingerResponseStatusError
+		if !errors.As(err, &statusError) {
+			t.Fatal(err)
+		}
+
+		if statusError.Body != nil || statusError.Problem != nil || statusError.RetryAfter != 30*time.Second {
+			t.Errorf("unexpected status error: %v", statusError)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package webfinger
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const DefaultMaxRedirects = 5
+
+var (
+	ErrTooManyRedirects  = errors.New("too many redirects")
+	ErrRedirectDowngrade = errors.New("redirect from https to http")
+	ErrRedirectCrossHost = errors.New("redirect to another host")
+	ErrRedirectLoop      = errors.New("redirect loop")
+)
+
+type RedirectPolicy struct {
+	MaxRedirects    int
+	RefuseCrossHost bool
+}
+
+func (policy *RedirectPolicy) apply(httpClient *http.Client) *http.Client {
+	return withCheckRedirect(httpClient, policy.check)
+}
+
+// refuseDowngrade is applied when no RedirectPolicy is set, because RFC 7033
+// forbids following a redirect from https to http regardless of policy.
+func refuseDowngrade(httpClient *http.Client) *http.Client {
+	return withCheckRedirect(httpClient, func(request *http.Request, via []*http.Request) error {
+		for _, v := range via {
+			if strings.EqualFold(v.URL.Scheme, "https") && !strings.EqualFold(request.URL.Scheme, "https") {
+				return &RedirectError{
+					Err:   ErrRedirectDowngrade,
+					Chain: redirectURLs(request, via),
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func withCheckRedirect(httpClient *http.Client, check func(*http.Request, []*http.Request) error) *http.Client {
+	checkRedirect := httpClient.CheckRedirect
+
+	c := *httpClient
+	c.CheckRedirect = func(request *http.Request, via []*http.Request) error {
+		if err := check(request, via); err != nil {
+			return err
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(request, via)
+		}
+
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		return nil
+	}
+
+	return &c
+}
+
+func redirectURLs(request *http.Request, via []*http.Request) []string {
+	chain := make([]string, 0, len(via)+1)
+	for _, v := range via {
+		chain = append(chain, v.URL.String())
+	}
+
+	return append(chain, request.URL.String())
+}
+
+func (policy *RedirectPolicy) check(request *http.Request, via []*http.Request) error {
+	maxRedirects := policy.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+
+	var err error
+	for _, v := range via {
+		switch {
+		case v.URL.String() == request.URL.String():
+			err = ErrRedirectLoop
+		case strings.EqualFold(v.URL.Scheme, "https") && !strings.EqualFold(request.URL.Scheme, "https"):
+			err = ErrRedirectDowngrade
+		case policy.RefuseCrossHost && !strings.EqualFold(v.URL.Host, request.URL.Host):
+			err = ErrRedirectCrossHost
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil && len(via) > maxRedirects {
+		err = ErrTooManyRedirects
+	}
+
+	if err != nil {
+		return &RedirectError{
+			Err:   err,
+			Chain: redirectURLs(request, via),
+		}
+	}
+
+	return nil
+}
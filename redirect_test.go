@@ -0,0 +1,168 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_DoResult_Redirect(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/webfinger":
+			http.Redirect(w, r, "/step1?"+r.URL.RawQuery, http.StatusFound)
+		case "/step1":
+			http.Redirect(w, r, "/step2?"+r.URL.RawQuery, http.StatusMovedPermanently)
+		case "/step2":
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:     http.DefaultClient,
+		HTTPMode:       true,
+		RedirectPolicy: &webfinger.RedirectPolicy{},
+	}
+
+	result, err := client.DoResult(context.Background(), &webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Message.Subject != "acct:test@localhost" {
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(result.URL, testServer.URL+"/step2?") {
+		t.Errorf("unexpected url: %s", result.URL)
+	}
+
+	if len(result.RedirectChain) != 2 || !strings.HasPrefix(result.RedirectChain[0], testServer.URL+"/.well-known/webfinger?") || !strings.HasPrefix(result.RedirectChain[1], testServer.URL+"/step1?") {
+		t.Errorf("unexpected redirect chain: %v", result.RedirectChain)
+	}
+}
+
+func Test_Client_Do_RedirectPolicy(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+	}))
+	defer httpServer.Close()
+
+	var tlsServerURL string
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resource") {
+		case "acct:downgrade@localhost":
+			http.Redirect(w, r, httpServer.URL+"/.well-known/webfinger", http.StatusFound)
+		case "acct:crosshost@localhost":
+			http.Redirect(w, r, strings.Replace(tlsServerURL, "127.0.0.1", "localhost", 1)+"/.well-known/webfinger", http.StatusFound)
+		case "acct:loop@localhost":
+			if r.URL.Path == "/.well-known/webfinger" {
+				http.Redirect(w, r, "/loop?"+r.URL.RawQuery, http.StatusFound)
+			} else {
+				http.Redirect(w, r, "/.well-known/webfinger?"+r.URL.RawQuery, http.StatusFound)
+			}
+		case "acct:toomany@localhost":
+			http.Redirect(w, r, r.URL.Path+"x?"+r.URL.RawQuery, http.StatusFound)
+		default:
+			t.Errorf("unexpected resource: %s", r.URL.Query().Get("resource"))
+		}
+	}))
+	defer tlsServer.Close()
+
+	tlsServerURL = tlsServer.URL
+
+	u, err := url.Parse(tlsServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: tlsServer.Client(),
+		RedirectPolicy: &webfinger.RedirectPolicy{
+			MaxRedirects:    3,
+			RefuseCrossHost: true,
+		},
+	}
+
+	tests := []struct {
+		Resource string
+		Expected error
+	}{
+		{Resource: "acct:downgrade@localhost", Expected: webfinger.ErrRedirectDowngrade},
+		{Resource: "acct:crosshost@localhost", Expected: webfinger.ErrRedirectCrossHost},
+		{Resource: "acct:loop@localhost", Expected: webfinger.ErrRedirectLoop},
+		{Resource: "acct:toomany@localhost", Expected: webfinger.ErrTooManyRedirects},
+	}
+
+	for i, test := range tests {
+		_, err := client.Do(&webfinger.Request{Host: u.Host, Resource: test.Resource})
+
+		var redirectError *webfinger.RedirectError
+		if !errors.As(err, &redirectError) || !errors.Is(err, test.Expected) {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+			continue
+		}
+
+		if len(redirectError.Chain) < 2 || !strings.HasPrefix(redirectError.Chain[0], tlsServer.URL+"/.well-known/webfinger?") {
+			t.Logf("case_index: %d, unexpected redirect chain: %v", i, redirectError.Chain)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_Do_RedirectDowngrade_Default(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+	}))
+	defer httpServer.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resource") {
+		case "acct:downgrade@localhost":
+			http.Redirect(w, r, httpServer.URL+"/.well-known/webfinger", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/step1?"+r.URL.RawQuery, http.StatusFound)
+		}
+	}))
+	defer tlsServer.Close()
+
+	u, err := url.Parse(tlsServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient: tlsServer.Client(),
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:downgrade@localhost"})
+
+	var redirectError *webfinger.RedirectError
+	if !errors.As(err, &redirectError) || !errors.Is(err, webfinger.ErrRedirectDowngrade) {
+		t.Error(err)
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:toomany@localhost"})
+	if err == nil || errors.As(err, &redirectError) {
+		t.Error(err)
+	}
+}
@@ -1,6 +1,8 @@
 package webfinger
 
 type Request struct {
-	Host     string
-	Resource string
+	Host           string
+	Resource       string
+	Rels           []string
+	ValidationMode ValidationMode
 }
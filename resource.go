@@ -0,0 +1,174 @@
+package webfinger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+func ParseResource(resource string) (string, string, error) {
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return "", "", fmt.Errorf("%w: empty resource", ErrInvalidResource)
+	}
+
+	if !strings.ContainsAny(resource, ":@") {
+		unescaped, err := url.PathUnescape(resource)
+		if err == nil {
+			resource = unescaped
+		}
+	}
+
+	scheme, rest, found := strings.Cut(resource, ":")
+	switch {
+	case found && strings.EqualFold(scheme, "acct"):
+		return parseAccountResource("acct", rest)
+	case found && strings.EqualFold(scheme, "mailto"):
+		return parseAccountResource("mailto", rest)
+	case found && (strings.EqualFold(scheme, "http") || strings.EqualFold(scheme, "https")):
+		return parseURLResource(resource)
+	case !found || strings.Contains(scheme, "@"):
+		return parseAccountResource("acct", strings.TrimPrefix(resource, "@"))
+	default:
+		return "", "", fmt.Errorf("%w: unsupported scheme: %s", ErrInvalidResource, scheme)
+	}
+}
+
+func (client *Client) Lookup(ctx context.Context, resource string, rels ...string) (*Message, error) {
+	normalized, host, err := ParseResource(resource)
+	if err != nil {
+		return nil, &Error{
+			Err: err,
+		}
+	}
+
+	return client.DoContext(ctx, &Request{
+		Host:     host,
+		Resource: normalized,
+		Rels:     rels,
+	})
+}
+
+func parseAccountResource(scheme string, account string) (string, string, error) {
+	if !strings.Contains(account, "@") {
+		unescaped, err := url.PathUnescape(account)
+		if err == nil {
+			account = unescaped
+		}
+	}
+
+	i := strings.LastIndex(account, "@")
+	if i <= 0 || i == len(account)-1 {
+		return "", "", fmt.Errorf("%w: missing user or host: %s", ErrInvalidResource, account)
+	}
+
+	user, err := url.PathUnescape(account[:i])
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrInvalidResource, err)
+	}
+
+	host, err := normalizeHost(account[i+1:])
+	if err != nil {
+		return "", "", err
+	}
+
+	return scheme + ":" + escapeAccountUser(user) + "@" + host, host, nil
+}
+
+func parseURLResource(resource string) (string, string, error) {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrInvalidResource, err)
+	}
+
+	host, err := normalizeHost(u.Host)
+	if err != nil {
+		return "", "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = host
+	if u.User != nil {
+		u.User = url.User(u.User.Username())
+	}
+
+	return u.String(), host, nil
+}
+
+func normalizeHost(hostport string) (string, error) {
+	if hostport == "" {
+		return "", fmt.Errorf("%w: empty host", ErrInvalidResource)
+	}
+
+	host, port := hostport, ""
+	if strings.HasPrefix(hostport, "[") || strings.Count(hostport, ":") == 1 {
+		h, p, err := net.SplitHostPort(hostport)
+		if err != nil {
+			if !strings.HasPrefix(hostport, "[") || !strings.HasSuffix(hostport, "]") {
+				return "", fmt.Errorf("%w: %w", ErrInvalidResource, err)
+			}
+			h = strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+		} else if n, err := strconv.ParseUint(p, 10, 16); err != nil || n == 0 {
+			return "", fmt.Errorf("%w: invalid port: %s", ErrInvalidResource, hostport)
+		}
+		host, port = h, p
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() == nil {
+			host = "[" + ip.String() + "]"
+		} else {
+			host = ip.String()
+		}
+	} else {
+		asciiHost, err := toASCIIHost(host)
+		if err != nil {
+			return "", err
+		}
+		host = asciiHost
+	}
+
+	if port != "" {
+		return host + ":" + port, nil
+	}
+
+	return host, nil
+}
+
+func toASCIIHost(host string) (string, error) {
+	if strings.TrimSuffix(host, ".") == "" {
+		return "", fmt.Errorf("%w: empty host", ErrInvalidResource)
+	}
+
+	asciiHost, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid host: %s: %w", ErrInvalidResource, host, err)
+	}
+
+	asciiHost = strings.TrimSuffix(asciiHost, ".")
+	if slices.Contains(strings.Split(asciiHost, "."), "") {
+		return "", fmt.Errorf("%w: empty host label: %s", ErrInvalidResource, host)
+	}
+
+	return asciiHost, nil
+}
+
+func escapeAccountUser(user string) string {
+	var sb strings.Builder
+	for i := 0; i < len(user); i++ {
+		c := user[i]
+		if c == '@' || c == '%' || c == '/' || c == '?' || c == '#' || c <= 0x20 || c >= 0x7f {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
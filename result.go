@@ -0,0 +1,72 @@
+package webfinger
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+)
+
+type LookupResult struct {
+	Message          *Message
+	URL              string
+	RedirectChain    []string
+	StatusCode       int
+	Header           http.Header
+	ContentType      string
+	Elapsed          time.Duration
+	FromCache        bool
+	Revalidated      bool
+	HostMetaFallback bool
+	Warnings         []Violation
+}
+
+func newLookupResult(webFingerMessage *Message, response *http.Response) *LookupResult {
+	result := &LookupResult{
+		Message: webFingerMessage,
+	}
+
+	if response != nil {
+		result.StatusCode = response.StatusCode
+		result.Header = response.Header
+		result.ContentType = contentType(response.Header)
+	}
+
+	if response != nil && response.Request != nil {
+		result.URL = response.Request.URL.String()
+		result.RedirectChain = redirectChain(response)
+	}
+
+	return result
+}
+
+func (r *LookupResult) clone() *LookupResult {
+	dst := *r
+	if r.Message != nil {
+		dst.Message = r.Message.clone()
+	}
+	dst.RedirectChain = slices.Clone(r.RedirectChain)
+	dst.Header = r.Header.Clone()
+	dst.Warnings = slices.Clone(r.Warnings)
+
+	return &dst
+}
+
+func contentType(header http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+func redirectChain(response *http.Response) []string {
+	var chain []string
+	for request := response.Request; request.Response != nil && request.Response.Request != nil; request = request.Response.Request {
+		chain = append(chain, request.Response.Request.URL.String())
+	}
+	slices.Reverse(chain)
+
+	return chain
+}
@@ -0,0 +1,82 @@
+package webfinger_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_DoResult_Metadata(t *testing.T) {
+	var baseURL string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/.well-known/webfinger" && r.URL.Query().Get("resource") == "acct:legacy@localhost":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/.well-known/webfinger":
+			w.Header().Set("Content-Type", "application/jrd+json; charset=utf-8")
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("X-Test", "test")
+			io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+		case r.URL.Path == "/.well-known/host-meta":
+			w.Header().Set("Content-Type", "application/xrd+xml")
+			io.WriteString(w, `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Link rel="lrdd" template="`+baseURL+`/describe?uri={uri}"/></XRD>`)
+		case r.URL.Path == "/describe":
+			w.Header().Set("Content-Type", "application/xrd+xml")
+			io.WriteString(w, `<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0"><Subject>acct:legacy@localhost</Subject></XRD>`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	baseURL = testServer.URL
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		HostMetaFallback: true,
+		Cache:            webfinger.NewLRUCache(0),
+	}
+
+	result, err := client.DoResult(context.Background(), &webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.StatusCode != http.StatusOK || result.ContentType != "application/jrd+json" || result.Header.Get("X-Test") != "test" {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	if result.FromCache || result.HostMetaFallback || result.Elapsed <= 0 {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	result, err = client.DoResult(context.Background(), &webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.FromCache || result.StatusCode != http.StatusOK || result.ContentType != "application/jrd+json" || result.Header.Get("X-Test") != "test" {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	result, err = client.DoResult(context.Background(), &webfinger.Request{Host: u.Host, Resource: "acct:legacy@localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.HostMetaFallback || result.ContentType != "application/xrd+xml" || result.URL != testServer.URL+"/describe?uri=acct%3Alegacy%40localhost" {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
@@ -0,0 +1,166 @@
+package webfinger
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 200 * time.Millisecond
+	DefaultRetryMaxDelay    = 10 * time.Second
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	baseDelay := policy.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+
+	maxDelay := policy.maxDelay()
+
+	delay := min(baseDelay, maxDelay)
+	for range attempt {
+		delay *= 2
+		if delay >= maxDelay {
+			delay = maxDelay
+			break
+		}
+	}
+
+	return rand.N(delay) + 1
+}
+
+func (policy *RetryPolicy) maxAttempts() int {
+	if policy.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+
+	return policy.MaxAttempts
+}
+
+func (policy *RetryPolicy) maxDelay() time.Duration {
+	if policy.MaxDelay <= 0 {
+		return DefaultRetryMaxDelay
+	}
+
+	return policy.MaxDelay
+}
+
+func (client *Client) fetchMessageWithRetry(ctx context.Context, request *http.Request) (*Message, *http.Response, error) {
+	policy := client.RetryPolicy
+
+	attempts := 0
+	for {
+		attempts++
+
+		webFingerMessage, response, err := client.fetchMessageOnce(request.Clone(ctx))
+		if err == nil || attempts >= policy.maxAttempts() || !isRetryable(ctx, response, err) {
+			return webFingerMessage, response, retryError(attempts, err)
+		}
+
+		delay := policy.backoff(attempts - 1)
+		if retryAfter, ok := parseRetryAfter(response, time.Now()); ok {
+			if retryAfter > policy.maxDelay() {
+				return nil, response, retryError(attempts, err)
+			}
+			delay = max(delay, retryAfter)
+		}
+
+		retryEvent := RetryEvent{
+			Host:    request.URL.Host,
+			Attempt: attempts,
+			Delay:   delay,
+		}
+		if response != nil {
+			retryEvent.StatusCode = response.StatusCode
+		}
+		client.observer().Retry(ctx, retryEvent)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, response, &Error{
+				Err: &RetryError{
+					Attempts: attempts,
+					Err:      contextError(ctx, unwrapError(err)),
+				},
+			}
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetryable(ctx context.Context, response *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if response != nil {
+		return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	}
+
+	var rateLimitedError *RateLimitedError
+	if errors.As(err, &rateLimitedError) {
+		return false
+	}
+
+	return IsTemporary(err)
+}
+
+func parseRetryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(t.Sub(now), 0), true
+}
+
+func retryError(attempts int, err error) error {
+	if err == nil || attempts <= 1 {
+		return err
+	}
+
+	return &Error{
+		Err: &RetryError{
+			Attempts: attempts,
+			Err:      unwrapError(err),
+		},
+	}
+}
+
+func unwrapError(err error) error {
+	if webFingerError, ok := err.(*Error); ok && webFingerError.Err != nil {
+		return webFingerError.Err
+	}
+
+	return err
+}
@@ -0,0 +1,178 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Client_Do_Retry_Success(t *testing.T) {
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		switch attempt {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("Content-Type", "application/jrd+json")
+			io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+		}
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		RetryPolicy: &webfinger.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+		},
+	}
+
+	message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Subject != "acct:test@localhost" || count.Load() != 3 {
+		t.Errorf("unexpected result: %s, %d", message.Subject, count.Load())
+	}
+}
+
+func Test_Client_Do_Retry_Exhausted(t *testing.T) {
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		RetryPolicy: &webfinger.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+		},
+	}
+
+	_, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+
+	var retryError *webfinger.RetryError
+	var webFingerResponseStatusError *webfinger.WebFingerResponseStatusError
+	if !errors.As(err, &retryError) || !errors.As(err, &webFingerResponseStatusError) {
+		t.Fatal(err)
+	}
+
+	if retryError.Attempts != 3 || count.Load() != 3 || webFingerResponseStatusError.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("unexpected result: %d, %d", retryError.Attempts, count.Load())
+	}
+}
+
+func Test_Client_Do_Retry_DefaultMaxAttempts(t *testing.T) {
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		RetryPolicy: &webfinger.RetryPolicy{
+			BaseDelay: time.Millisecond,
+		},
+	}
+
+	_, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+
+	var retryError *webfinger.RetryError
+	if !errors.As(err, &retryError) {
+		t.Fatal(err)
+	}
+
+	if retryError.Attempts != webfinger.DefaultRetryMaxAttempts || count.Load() != webfinger.DefaultRetryMaxAttempts {
+		t.Errorf("unexpected result: %d, %d", retryError.Attempts, count.Load())
+	}
+}
+
+func Test_Client_Do_Retry_NotRetryable(t *testing.T) {
+	tests := []int{http.StatusNotFound, http.StatusGone, http.StatusBadRequest}
+
+	for i, test := range tests {
+		testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+			w.WriteHeader(test)
+		})
+
+		client := &webfinger.Client{
+			HTTPClient: http.DefaultClient,
+			HTTPMode:   true,
+			RetryPolicy: &webfinger.RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Millisecond,
+			},
+		}
+
+		_, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+
+		var retryError *webfinger.RetryError
+		if err == nil || errors.As(err, &retryError) || count.Load() != 1 {
+			t.Logf("case_index: %d, unexpected result: %v, %d", i, err, count.Load())
+			t.Fail()
+		}
+
+		testServer.Close()
+	}
+}
+
+func Test_Client_Do_Retry_RetryAfterTooLong(t *testing.T) {
+	testServer, host, count := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		RetryPolicy: &webfinger.RetryPolicy{
+			MaxAttempts: 3,
+			MaxDelay:    time.Second,
+		},
+	}
+
+	_, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+	if err == nil || count.Load() != 1 {
+		t.Errorf("unexpected result: %v, %d", err, count.Load())
+	}
+}
+
+func Test_Client_Do_Retry_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testServer, host, _ := newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient: http.DefaultClient,
+		HTTPMode:   true,
+		RetryPolicy: &webfinger.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Second,
+		},
+	}
+
+	_, err := client.DoContext(ctx, &webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+
+	var webFingerError *webfinger.Error
+	if !errors.As(err, &webFingerError) || !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,77 @@
+package webfinger
+
+import (
+	"context"
+	"sync"
+)
+
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	result  *LookupResult
+	err     error
+}
+
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) (*LookupResult, error)) (*LookupResult, error) {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = call
+
+		go func() {
+			defer close(call.done)
+			defer cancel()
+
+			result, err := fn(callCtx)
+
+			g.mutex.Lock()
+			call.result, call.err = result, err
+			g.forget(key, call)
+			g.mutex.Unlock()
+		}()
+	}
+	call.waiters++
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		if call.result == nil {
+			return nil, call.err
+		}
+		return call.result.clone(), call.err
+
+	case <-ctx.Done():
+		g.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			g.forget(key, call)
+		}
+		g.mutex.Unlock()
+
+		return nil, &Error{
+			Err: ctx.Err(),
+		}
+	}
+}
+
+func (g *flightGroup) forget(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,115 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func newSingleflightTestServer(t *testing.T, release <-chan struct{}) (*httptest.Server, string, *atomic.Int32) {
+	return newWebFingerTestServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+		<-release
+
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:test@localhost"}`)
+	})
+}
+
+func Test_Client_Do_CoalesceRequests(t *testing.T) {
+	release := make(chan struct{})
+	testServer, host, count := newSingleflightTestServer(t, release)
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		CoalesceRequests: true,
+	}
+
+	messages := make([]*webfinger.Message, 10)
+	var wg sync.WaitGroup
+	for i := range messages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			messages[i] = message
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if count.Load() != 1 {
+		t.Errorf("unexpected request count: %d", count.Load())
+	}
+
+	seen := map[*webfinger.Message]bool{}
+	for _, message := range messages {
+		if message == nil || message.Subject != "acct:test@localhost" || seen[message] {
+			t.Fatalf("unexpected message: %v", message)
+		}
+		seen[message] = true
+	}
+}
+
+func Test_Client_Do_CoalesceRequests_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	testServer, host, count := newSingleflightTestServer(t, release)
+	defer testServer.Close()
+
+	client := &webfinger.Client{
+		HTTPClient:       http.DefaultClient,
+		HTTPMode:         true,
+		CoalesceRequests: true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	canceled := make(chan error)
+	go func() {
+		_, err := client.DoContext(ctx, &webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+		canceled <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	waiting := make(chan *webfinger.Message)
+	go func() {
+		message, err := client.Do(&webfinger.Request{Host: host, Resource: "acct:test@localhost"})
+		if err != nil {
+			t.Error(err)
+		}
+		waiting <- message
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+
+	close(release)
+
+	if message := <-waiting; message == nil || message.Subject != "acct:test@localhost" {
+		t.Errorf("unexpected message: %v", message)
+	}
+
+	if count.Load() != 1 {
+		t.Errorf("unexpected request count: %d", count.Load())
+	}
+}
@@ -0,0 +1,116 @@
+package webfinger
+
+import (
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"slices"
+)
+
+type ValidationMode int
+
+const (
+	ValidationDefault ValidationMode = iota
+	ValidationNone
+	ValidationLenient
+	ValidationStrict
+)
+
+type Violation struct {
+	Field       string
+	Description string
+}
+
+func (v Violation) String() string {
+	return v.Field + ": " + v.Description
+}
+
+func (r Message) Validate() error {
+	violations := r.violations()
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Violations: violations,
+	}
+}
+
+func (r Message) violations() []Violation {
+	var violations []Violation
+	add := func(field string, format string, args ...any) {
+		violations = append(violations, Violation{
+			Field:       field,
+			Description: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if r.Subject == "" {
+		add("subject", "missing")
+	} else if !isAbsoluteURI(r.Subject) {
+		add("subject", "not an absolute URI: %s", r.Subject)
+	}
+
+	for i, alias := range r.Aliases {
+		if !isAbsoluteURI(alias) {
+			add(fmt.Sprintf("aliases[%d]", i), "not an absolute URI: %s", alias)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(r.Properties)) {
+		if !isAbsoluteURI(name) {
+			add("properties", "property name is not an absolute URI: %s", name)
+		}
+	}
+
+	for i, link := range r.Links {
+		field := fmt.Sprintf("links[%d]", i)
+
+		if link.Rel == "" {
+			add(field+".rel", "missing")
+		}
+
+		if link.Href != "" && !isAbsoluteURI(link.Href) {
+			add(field+".href", "not an absolute URI: %s", link.Href)
+		}
+
+		if link.Type != "" {
+			if _, _, err := mime.ParseMediaType(link.Type); err != nil {
+				add(field+".type", "invalid media type: %s", link.Type)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(link.Properties)) {
+			if !isAbsoluteURI(name) {
+				add(field+".properties", "property name is not an absolute URI: %s", name)
+			}
+		}
+	}
+
+	return violations
+}
+
+func (r Message) resourceViolations(resource string) []Violation {
+	if r.Subject == "" || sameResource(r.Subject, resource) {
+		return nil
+	}
+
+	for _, alias := range r.Aliases {
+		if sameResource(alias, resource) {
+			return nil
+		}
+	}
+
+	return []Violation{
+		{
+			Field:       "subject",
+			Description: fmt.Sprintf("does not match requested resource or aliases: %s", resource),
+		},
+	}
+}
+
+func isAbsoluteURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
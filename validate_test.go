@@ -0,0 +1,155 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/MitarashiDango/go-nullable"
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_Message_Validate_Valid(t *testing.T) {
+	m := webfinger.Message{
+		Subject: "acct:test@localhost",
+		Aliases: []string{"http://localhost/@test"},
+		Properties: webfinger.Properties{
+			"http://localhost/ns/test": nullable.NewString("test"),
+		},
+		Links: []webfinger.Link{
+			{
+				Rel:  "self",
+				Type: `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`,
+				Href: "http://localhost/users/test",
+			},
+		},
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Message_Validate_Invalid(t *testing.T) {
+	m := webfinger.Message{
+		Aliases: []string{"/@test"},
+		Properties: webfinger.Properties{
+			"test": nullable.NewString("test"),
+		},
+		Links: []webfinger.Link{
+			{
+				Type: "text/html",
+				Href: "/@test",
+			},
+			{
+				Rel:  "self",
+				Type: "application/",
+				Href: "http://localhost/users/test",
+			},
+		},
+	}
+
+	err := m.Validate()
+
+	var validationError *webfinger.ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatal(err)
+	}
+
+	expected := []string{"subject", "aliases[0]", "properties", "links[0].rel", "links[0].href", "links[1].type"}
+	if len(validationError.Violations) != len(expected) {
+		t.Fatalf("unexpected violations: %v", validationError.Violations)
+	}
+
+	for i, field := range expected {
+		if validationError.Violations[i].Field != field {
+			t.Logf("case_index: %d, expected: %v, actual: %v", i, field, validationError.Violations[i].Field)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_DoResult_ValidationMode(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:other@localhost","links":[{"href":"/@other"}]}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:     http.DefaultClient,
+		HTTPMode:       true,
+		ValidationMode: webfinger.ValidationStrict,
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:test@localhost"})
+
+	var webFingerError *webfinger.Error
+	var validationError *webfinger.ValidationError
+	if !errors.As(err, &webFingerError) || !errors.As(err, &validationError) {
+		t.Fatal(err)
+	}
+
+	if len(validationError.Violations) != 3 {
+		t.Errorf("unexpected violations: %v", validationError.Violations)
+	}
+
+	result, err := client.DoResult(context.Background(), &webfinger.Request{Host: u.Host, Resource: "acct:test@localhost", ValidationMode: webfinger.ValidationLenient})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Warnings) != 3 || result.Message.Subject != "acct:other@localhost" {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+
+	result, err = client.DoResult(context.Background(), &webfinger.Request{Host: u.Host, Resource: "acct:test@localhost", ValidationMode: webfinger.ValidationNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+}
+
+func Test_Client_DoResult_ValidationStrict_NormalizedResource(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:alice@Example.com","aliases":["HTTPS://Example.COM/@alice"]}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:     http.DefaultClient,
+		HTTPMode:       true,
+		ValidationMode: webfinger.ValidationStrict,
+	}
+
+	for _, resource := range []string{"acct:alice@example.com", "https://example.com/@alice"} {
+		if _, err := client.Do(&webfinger.Request{Host: u.Host, Resource: resource}); err != nil {
+			t.Errorf("%s: %v", resource, err)
+		}
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:bob@example.com"})
+
+	var validationError *webfinger.ValidationError
+	if !errors.As(err, &validationError) {
+		t.Error(err)
+	}
+}
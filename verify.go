@@ -0,0 +1,95 @@
+package webfinger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+)
+
+var (
+	ErrSubjectMismatch = errors.New("subject and aliases do not match resource")
+	ErrHostMismatch    = errors.New("host is not authoritative for resource")
+)
+
+func VerifyBinding(resource string, host string, message *Message) error {
+	_, resourceHost, err := ParseResource(resource)
+	if err != nil {
+		return err
+	}
+
+	bindingError := &BindingError{
+		Resource: resource,
+		Subject:  message.Subject,
+		Host:     host,
+	}
+
+	matched := false
+	for _, candidate := range append([]string{message.Subject}, message.Aliases...) {
+		if candidate == "" {
+			continue
+		}
+
+		if sameResource(candidate, resource) {
+			matched = true
+			break
+		}
+	}
+
+	if !matched {
+		bindingError.Err = ErrSubjectMismatch
+		return bindingError
+	}
+
+	normalizedHost, err := normalizeHost(host)
+	if err != nil || normalizedHost != resourceHost {
+		bindingError.Err = ErrHostMismatch
+		return bindingError
+	}
+
+	return nil
+}
+
+// verifyBinding checks authority against the queried host, so redirects and
+// LRDD results served from another host keep the authority of the first hop.
+func (client *Client) verifyBinding(ctx context.Context, webFingerRequest *Request, result *LookupResult) error {
+	host := webFingerRequest.Host
+	if err := VerifyBinding(webFingerRequest.Resource, host, result.Message); err != nil {
+		var bindingError *BindingError
+		if errors.As(err, &bindingError) {
+			client.log(ctx, slog.LevelWarn, "webfinger protocol violation",
+				slog.String("host", host),
+				slog.String("error", bindingError.Err.Error()))
+		}
+
+		return &Error{
+			Err: &PolicyError{
+				Host: host,
+				URL:  result.URL,
+				Err:  err,
+			},
+		}
+	}
+
+	return nil
+}
+
+func resultHost(webFingerRequest *Request, result *LookupResult) string {
+	if u, err := url.Parse(result.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return webFingerRequest.Host
+}
+
+// sameResource compares resources after ParseResource normalization and falls
+// back to exact equality when either side does not parse.
+func sameResource(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	normalizedA, _, errA := ParseResource(a)
+	normalizedB, _, errB := ParseResource(b)
+	return errA == nil && errB == nil && normalizedA == normalizedB
+}
@@ -0,0 +1,154 @@
+package webfinger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	webfinger "github.com/MitarashiDango/go-webfinger"
+)
+
+func Test_VerifyBinding(t *testing.T) {
+	tests := []struct {
+		Resource string
+		Host     string
+		Message  webfinger.Message
+		Expected error
+	}{
+		{
+			Resource: "acct:test@example.com",
+			Host:     "example.com",
+			Message:  webfinger.Message{Subject: "acct:test@example.com"},
+			Expected: nil,
+		},
+		{
+			Resource: "@test@Example.COM",
+			Host:     "EXAMPLE.com",
+			Message:  webfinger.Message{Subject: "acct:test@example.com"},
+			Expected: nil,
+		},
+		{
+			Resource: "https://example.com/@test",
+			Host:     "example.com",
+			Message: webfinger.Message{
+				Subject: "acct:test@example.com",
+				Aliases: []string{"https://EXAMPLE.com/@test"},
+			},
+			Expected: nil,
+		},
+		{
+			Resource: "acct:test@example.com",
+			Host:     "example.com",
+			Message:  webfinger.Message{Subject: "acct:admin@example.com"},
+			Expected: webfinger.ErrSubjectMismatch,
+		},
+		{
+			Resource: "acct:test@example.com",
+			Host:     "evil.example",
+			Message:  webfinger.Message{Subject: "acct:test@example.com"},
+			Expected: webfinger.ErrHostMismatch,
+		},
+	}
+
+	for i, test := range tests {
+		err := webfinger.VerifyBinding(test.Resource, test.Host, &test.Message)
+		if test.Expected == nil {
+			if err != nil {
+				t.Logf("case_index: %d, unexpected error: %v", i, err)
+				t.Fail()
+			}
+			continue
+		}
+
+		var bindingError *webfinger.BindingError
+		if !errors.As(err, &bindingError) || !errors.Is(err, test.Expected) {
+			t.Logf("case_index: %d, unexpected error: %v", i, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Client_Do_VerifyBinding(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:admin@`+r.Host+`","aliases":["acct:alias@`+r.Host+`"]}`)
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:    http.DefaultClient,
+		HTTPMode:      true,
+		VerifyBinding: true,
+	}
+
+	if _, err := client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:alias@" + u.Host}); err != nil {
+		t.Error(err)
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:test@" + u.Host})
+
+	var webFingerError *webfinger.Error
+	if !errors.As(err, &webFingerError) || !errors.Is(err, webfinger.ErrSubjectMismatch) {
+		t.Error(err)
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: u.Host, Resource: "acct:admin@example.com"})
+	if !errors.Is(err, webfinger.ErrSubjectMismatch) {
+		t.Error(err)
+	}
+}
+
+func Test_Client_Do_VerifyBinding_Redirect(t *testing.T) {
+	var accountHost string
+
+	webServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jrd+json")
+		io.WriteString(w, `{"subject":"acct:alice@`+accountHost+`"}`)
+	}))
+	defer webServer.Close()
+
+	accountServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, webServer.URL+r.URL.RequestURI(), http.StatusMovedPermanently)
+	}))
+	defer accountServer.Close()
+
+	u, err := url.Parse(accountServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+	accountHost = u.Host
+
+	webURL, err := url.Parse(webServer.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	client := &webfinger.Client{
+		HTTPClient:    http.DefaultClient,
+		HTTPMode:      true,
+		VerifyBinding: true,
+	}
+
+	result, err := client.DoResult(context.Background(), &webfinger.Request{Host: accountHost, Resource: "acct:alice@" + accountHost})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.RedirectChain) != 1 {
+		t.Errorf("unexpected redirect chain: %v", result.RedirectChain)
+	}
+
+	_, err = client.Do(&webfinger.Request{Host: webURL.Host, Resource: "acct:alice@" + accountHost})
+	if !errors.Is(err, webfinger.ErrHostMismatch) {
+		t.Error(err)
+	}
+}